@@ -0,0 +1,234 @@
+package graphflow
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Builder supports the fluent construction of a Graphflow. Tasks passed to the Builder are added to the graphflow
+// automatically and joined by Paths in the order they're given, so the graph reads top to bottom in code.
+//
+// Example:
+//
+//	gf, err := graphflow.New().
+//		Start().
+//		If(isTheSkyCloudy).
+//		Yes(forecastRain).
+//		No(forecastSun).
+//		Join().
+//		End().
+//		Build()
+//
+// Any mistakes made while building are collected and returned together by Build, along with any
+// validation errors for the finished graphflow.
+type Builder struct {
+	gf       *Graphflow
+	ends     []pathEnd
+	branches []*branch
+	group    *TaskGroup
+	endTasks map[string]*EndTask
+	errs     []error
+}
+
+// pathEnd is the loose end of a Path that'll be joined to the next Task given to the Builder
+type pathEnd struct {
+	from      TaskIntf
+	condition PathCondition
+}
+
+// branch tracks a question Task opened with If until its YES and NO branches are joined back together
+type branch struct {
+	question TaskIntf
+	yes      bool
+	no       bool
+	open     bool
+	joined   []pathEnd
+}
+
+// New returns a Builder for a new, empty Graphflow
+func New() *Builder {
+	return &Builder{
+		gf:       new(Graphflow),
+		endTasks: make(map[string]*EndTask),
+	}
+}
+
+// Start adds the StartTask to the graphflow. All graphflows built with a Builder should begin with Start.
+func (b *Builder) Start() *Builder {
+	start := new(StartTask)
+	b.add(start)
+	b.ends = []pathEnd{{from: start, condition: ALWAYS}}
+	return b
+}
+
+// Then adds task to the graphflow, with an ALWAYS Path to it from the previous Task.
+// Passing a Task that's already been added joins the current Path to it instead.
+func (b *Builder) Then(task TaskIntf) *Builder {
+	if b.connect(task) {
+		b.ends = []pathEnd{{from: task, condition: ALWAYS}}
+	}
+	return b
+}
+
+// If adds a question Task to the graphflow. It should be followed by Yes and No branches and then Join.
+func (b *Builder) If(question TaskIntf) *Builder {
+	if b.connect(question) {
+		b.branches = append(b.branches, &branch{question: question})
+		b.ends = nil
+	}
+	return b
+}
+
+// Yes starts the YES branch of the most recent If with task
+func (b *Builder) Yes(task TaskIntf) *Builder {
+	return b.branch(YES, task)
+}
+
+// No starts the NO branch of the most recent If with task
+func (b *Builder) No(task TaskIntf) *Builder {
+	return b.branch(NO, task)
+}
+
+// Join ends the YES and NO branches of the most recent If, so the next Task is joined to the end of both of them.
+// A branch that has already been finished with End or EndWith isn't joined, and an If whose branches have both
+// been finished doesn't need to be joined at all.
+func (b *Builder) Join() *Builder {
+	br := b.currentBranch("Join")
+	if br == nil {
+		return b
+	}
+	b.closeBranch(br)
+	if !br.yes || !br.no {
+		b.errs = append(b.errs, fmt.Errorf("If(%s) needs both a Yes and a No branch before Join", br.question))
+	}
+	b.ends = br.joined
+	b.branches = b.branches[:len(b.branches)-1]
+	return b
+}
+
+// End finishes the current Path at the default EndTask
+func (b *Builder) End() *Builder {
+	return b.EndWith("")
+}
+
+// EndWith finishes the current Path at the EndTask for the named outcome, adding it to the graphflow
+// the first time the outcome is used
+func (b *Builder) EndWith(outcome string) *Builder {
+	endTask, exists := b.endTasks[outcome]
+	if !exists {
+		endTask = NewEndTask(outcome)
+		b.endTasks[outcome] = endTask
+	}
+	if b.connect(endTask) {
+		b.ends = nil
+	}
+	return b
+}
+
+// Group adds the Tasks that follow to a TaskGroup with the given name, until EndGroup is called
+func (b *Builder) Group(name string) *Builder {
+	for _, taskGroup := range b.gf.taskGroups {
+		if taskGroup.name == name {
+			b.group = taskGroup
+			return b
+		}
+	}
+	b.group = b.gf.NewTaskGroup(name)
+	return b
+}
+
+// EndGroup stops adding Tasks to the TaskGroup started with Group
+func (b *Builder) EndGroup() *Builder {
+	b.group = nil
+	return b
+}
+
+// Build returns the finished graphflow once it's been validated. The error returned combines every
+// mistake made while building the graphflow with any validation errors.
+func (b *Builder) Build() (*Graphflow, error) {
+	errs := b.errs
+	for i := len(b.branches) - 1; i >= 0; i-- {
+		// branches that finished at an EndTask don't need joining
+		br := b.branches[i]
+		b.closeBranch(br)
+		if len(br.joined) > 0 {
+			errs = append(errs, fmt.Errorf("If(%s) was never joined", br.question))
+		}
+	}
+	if err := b.gf.validateTasks(); err != nil {
+		errs = append(errs, err)
+	}
+	if len(errs) > 0 {
+		return nil, errors.Join(errs...)
+	}
+	return b.gf, nil
+}
+
+func (b *Builder) branch(condition PathCondition, task TaskIntf) *Builder {
+	name := "Yes"
+	if condition == NO {
+		name = "No"
+	}
+	br := b.currentBranch(name)
+	if br == nil {
+		return b
+	}
+	if (condition == YES && br.yes) || (condition == NO && br.no) {
+		b.errs = append(b.errs, fmt.Errorf("If(%s) already has a %s branch", br.question, name))
+		return b
+	}
+	b.closeBranch(br)
+	if condition == YES {
+		br.yes = true
+	} else {
+		br.no = true
+	}
+	br.open = true
+	b.ends = []pathEnd{{from: br.question, condition: condition}}
+	b.Then(task)
+	return b
+}
+
+func (b *Builder) currentBranch(method string) *branch {
+	if len(b.branches) == 0 {
+		b.errs = append(b.errs, fmt.Errorf("%s needs to follow an If", method))
+		return nil
+	}
+	return b.branches[len(b.branches)-1]
+}
+
+// closeBranch keeps the loose ends of the branch being built so they can be joined later
+func (b *Builder) closeBranch(br *branch) {
+	if br.open {
+		br.joined = append(br.joined, b.ends...)
+		br.open = false
+	}
+	b.ends = nil
+}
+
+// connect adds task to the graphflow if it hasn't been added already and joins the current loose ends to it
+func (b *Builder) connect(task TaskIntf) bool {
+	if task == nil {
+		b.errs = append(b.errs, errors.New("Builder can't add a nil Task"))
+		return false
+	}
+	if len(b.ends) == 0 {
+		b.errs = append(b.errs, fmt.Errorf("Task %s has no path leading to it", task))
+		return false
+	}
+	b.add(task)
+	for _, end := range b.ends {
+		b.gf.AddPath(end.from, end.condition, task)
+	}
+	return true
+}
+
+func (b *Builder) add(task TaskIntf) {
+	if b.gf.hasTask(task) {
+		return
+	}
+	b.gf.AddTask(task)
+	if b.group != nil {
+		b.group.AddTasks(task)
+	}
+}
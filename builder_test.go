@@ -0,0 +1,112 @@
+package graphflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuilderCloudySky(t *testing.T) {
+	ctx := new(ExecutionContext)
+	ctx.Set("Sky", "Cloudy")
+	ctx.Set("Forecast", "")
+
+	gf, err := New().
+		Start().
+		If(new(IsTheSkyCloudy)).
+		Yes(new(ForecastRain)).
+		No(new(ForecastSun)).
+		Join().
+		End().
+		Build()
+
+	assert.Nil(t, err)
+	assert.Len(t, gf.Tasks(), 5)
+
+	err = gf.Run(ctx)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Rain", ctx.Get("Forecast"))
+}
+
+func TestBuilderWithOutcomesAndGroups(t *testing.T) {
+	ctx := new(ExecutionContext)
+	ctx.Set("Sky", "Clear")
+
+	forecastRain := new(ForecastRain)
+	forecastSun := new(ForecastSun)
+	gf, err := New().
+		Start().
+		If(new(IsTheSkyCloudy)).
+		Group("Forecasting").
+		Yes(forecastRain).EndWith("Take Umbrella").
+		No(forecastSun).EndWith("Wear Sunglasses").
+		EndGroup().
+		Join().
+		Build()
+
+	assert.Nil(t, err)
+	assert.Len(t, gf.TaskGroups(), 1)
+	assert.Equal(t, []TaskIntf{forecastRain, gf.Tasks()[3], forecastSun, gf.Tasks()[5]}, gf.TaskGroups()[0].Tasks())
+
+	err = gf.Run(ctx)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Sun", ctx.Get("Forecast"))
+	assert.Equal(t, "Wear Sunglasses", gf.Result().Outcome)
+}
+
+func TestBuilderReusesTasks(t *testing.T) {
+	forecastSun := new(ForecastSun)
+	gf, err := New().
+		Start().
+		If(new(IsTheSkyCloudy)).
+		Yes(new(ForecastRain)).
+		Then(forecastSun).
+		No(forecastSun).
+		Join().
+		End().
+		Build()
+
+	assert.Nil(t, err)
+	assert.Len(t, gf.Tasks(), 5)
+}
+
+func TestBuilderAggregatesErrors(t *testing.T) {
+	gf, err := New().
+		Start().
+		Yes(new(ForecastRain)). // no If
+		If(new(IsTheSkyCloudy)).
+		Yes(new(ForecastRain)).
+		Join(). // no No branch
+		Then(nil).
+		Build()
+
+	assert.Nil(t, gf)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "Yes needs to follow an If")
+	assert.Contains(t, err.Error(), "needs both a Yes and a No branch")
+	assert.Contains(t, err.Error(), "nil Task")
+}
+
+func TestBuilderWithoutJoinThrowsError(t *testing.T) {
+	_, err := New().
+		Start().
+		If(new(IsTheSkyCloudy)).
+		Yes(new(ForecastRain)).
+		No(new(ForecastSun)).
+		Build()
+
+	assert.NotNil(t, err)
+}
+
+func TestBuilderWithEndedBranchesDoesntNeedJoin(t *testing.T) {
+	_, err := New().
+		Start().
+		If(new(IsTheSkyCloudy)).
+		Yes(new(ForecastRain)).EndWith("Take Umbrella").
+		No(new(ForecastSun)).EndWith("Wear Sunglasses").
+		Build()
+
+	assert.Nil(t, err)
+}
@@ -0,0 +1,192 @@
+package graphflow
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+)
+
+// Cloner can be implemented by Tasks that need more than a shallow copy of their struct when a graphflow is
+// cloned, eg because they hold maps, slices or pointers that shouldn't be shared between the copies
+type Cloner interface {
+	Clone() TaskIntf
+}
+
+// Clone returns an independent copy of the graphflow, with fresh Task instances in place of the originals.
+// Tasks implementing Cloner are copied by calling Clone, the rest by making a shallow copy of their struct.
+// The copy keeps the version, Task IDs, Paths, TaskGroups and Metadata of the original, but not its ExecutionContext
+// or RunResult, so a graphflow can be used as a template and cloned per tenant.
+func (gf *Graphflow) Clone() *Graphflow {
+	clone := new(Graphflow)
+	clone.version = gf.version
+	clone.recordContext = gf.recordContext
+	clones := make(map[TaskIntf]TaskIntf)
+	cloneOf := func(task TaskIntf) TaskIntf {
+		if _, exists := clones[task]; !exists {
+			clones[task] = cloneTask(task)
+		}
+		return clones[task]
+	}
+	for _, task := range gf.tasks {
+		clone.AddTaskWithID(gf.ids[task], cloneOf(task))
+		if metadata, exists := gf.metadata[task]; exists {
+			clone.SetMetadata(cloneOf(task), metadata)
+		}
+		if typeName, exists := gf.typeNames[task]; exists {
+			clone.setTypeName(cloneOf(task), typeName)
+		}
+	}
+	for _, p := range gf.OrderedPaths() {
+		clone.AddPath(cloneOf(p.From), p.Condition, cloneOf(p.To))
+	}
+	clone.copyTaskGroups(gf.taskGroups, cloneOf)
+	return clone
+}
+
+// Compose joins two graphflows one after the other, returning a new graphflow in which every Path that led
+// to the first graphflow's default EndTask leads on into the second graphflow instead. Both graphflows are
+// cloned, so they can be composed again.
+func Compose(first *Graphflow, second *Graphflow) (*Graphflow, error) {
+	gf := first.Clone()
+	var end TaskIntf
+	for _, task := range gf.tasks {
+		if endTask, isEndTask := task.(*EndTask); isEndTask && endTask.outcome == "" {
+			end = task
+		}
+	}
+	if end == nil {
+		return nil, errors.New("The first graphflow needs a default EndTask to be composed")
+	}
+	sub := second.Clone()
+	start, next, err := sub.entry()
+	if err != nil {
+		return nil, err
+	}
+	for _, edge := range gf.paths {
+		for condition, to := range edge {
+			if to == end {
+				edge[condition] = next
+			}
+		}
+	}
+	if err := gf.RemoveTask(end); err != nil {
+		return nil, err
+	}
+	gf.adopt(sub, start, false)
+	return gf, nil
+}
+
+// Splice inserts a copy of another graphflow into the Path leaving from with the given PathCondition. The Path
+// leads to the Task following the other graphflow's StartTask instead, and Paths that led to any of its EndTasks
+// lead to where the original Path used to.
+func (gf *Graphflow) Splice(from TaskIntf, condition PathCondition, sub *Graphflow) error {
+	to, exists := gf.paths[from][condition]
+	if !exists {
+		return fmt.Errorf("Task %s has no %s path", from, PathConditionName[condition])
+	}
+	clone := sub.Clone()
+	for _, edge := range clone.paths {
+		for c, t := range edge {
+			if _, isEndTask := t.(*EndTask); isEndTask {
+				edge[c] = to
+			}
+		}
+	}
+	start, next, err := clone.entry()
+	if err != nil {
+		return err
+	}
+	gf.adopt(clone, start, true)
+	gf.AddPath(from, condition, next)
+	return nil
+}
+
+// entry returns the default StartTask of the graphflow and the Task its ALWAYS Path leads to
+func (gf *Graphflow) entry() (TaskIntf, TaskIntf, error) {
+	start, err := gf.findStartTask("")
+	if err != nil {
+		return nil, nil, err
+	}
+	next, exists := gf.paths[start][ALWAYS]
+	if !exists {
+		return nil, nil, fmt.Errorf("Task %s needs an ALWAYS path to be composed", start)
+	}
+	return start, next, nil
+}
+
+// adopt moves the Tasks, Paths and TaskGroups of sub into the graphflow, leaving out the given StartTask and,
+// if skipEnds is set, any EndTasks. Task IDs are kept unless they're already in use.
+func (gf *Graphflow) adopt(sub *Graphflow, start TaskIntf, skipEnds bool) {
+	skip := func(task TaskIntf) bool {
+		_, isEndTask := task.(*EndTask)
+		return task == start || (skipEnds && isEndTask)
+	}
+	for _, task := range sub.tasks {
+		if skip(task) {
+			continue
+		}
+		if id := sub.ids[task]; gf.Task(id) == nil {
+			gf.AddTaskWithID(id, task)
+		} else {
+			gf.AddTask(task)
+		}
+		if metadata, exists := sub.metadata[task]; exists {
+			gf.SetMetadata(task, metadata)
+		}
+		if typeName, exists := sub.typeNames[task]; exists {
+			gf.setTypeName(task, typeName)
+		}
+	}
+	for _, p := range sub.OrderedPaths() {
+		if !skip(p.From) {
+			gf.AddPath(p.From, p.Condition, p.To)
+		}
+	}
+	gf.copyTaskGroups(sub.taskGroups, func(task TaskIntf) TaskIntf {
+		if skip(task) {
+			return nil
+		}
+		return task
+	})
+}
+
+// copyTaskGroups adds copies of TaskGroups to the graphflow, keeping how they're nested, their colours,
+// descriptions, error handlers and Policies. Each Task in them is replaced by the one returned by taskOf, and left
+// out if that's nil.
+func (gf *Graphflow) copyTaskGroups(taskGroups []*TaskGroup, taskOf func(TaskIntf) TaskIntf) {
+	copies := make(map[*TaskGroup]*TaskGroup)
+	for _, taskGroup := range taskGroups {
+		var taskGroupCopy *TaskGroup
+		if parent, nested := copies[taskGroup.parent]; nested {
+			taskGroupCopy = parent.NewTaskGroup(taskGroup.name)
+		} else {
+			taskGroupCopy = gf.NewTaskGroup(taskGroup.name)
+		}
+		taskGroupCopy.color = taskGroup.color
+		taskGroupCopy.description = taskGroup.description
+		taskGroupCopy.policy = taskGroup.policy
+		if taskGroup.errorHandler != nil {
+			taskGroupCopy.errorHandler = taskOf(taskGroup.errorHandler)
+		}
+		for _, task := range taskGroup.tasks {
+			if t := taskOf(task); t != nil {
+				taskGroupCopy.AddTasks(t)
+			}
+		}
+		copies[taskGroup] = taskGroupCopy
+	}
+}
+
+// cloneTask returns a copy of a Task, using its Clone method if it implements Cloner
+func cloneTask(task TaskIntf) TaskIntf {
+	if cloner, ok := task.(Cloner); ok {
+		return cloner.Clone()
+	}
+	v := reflect.ValueOf(task)
+	if v.Kind() != reflect.Ptr || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return task
+	}
+	clone := reflect.New(v.Elem().Type())
+	clone.Elem().Set(v.Elem())
+	return clone.Interface().(TaskIntf)
+}
@@ -0,0 +1,150 @@
+package graphflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// RecordForecasts is a Task struct that appends each Forecast to a history it owns
+type RecordForecasts struct {
+	Task
+	history *[]string
+}
+
+// String returns a description of the Task
+func (t *RecordForecasts) String() string {
+	return "Record Forecasts"
+}
+
+// Execute appends the ExecutionContext's Forecast value to the Task's history
+func (t *RecordForecasts) Execute(ctx *ExecutionContext) error {
+	*t.history = append(*t.history, ctx.Get("Forecast").(string))
+	return nil
+}
+
+// Clone gives the copy a history of its own
+func (t *RecordForecasts) Clone() TaskIntf {
+	return &RecordForecasts{history: new([]string)}
+}
+
+func buildRecordingGraphflow() *Graphflow {
+	gf := new(Graphflow)
+
+	// create task instances
+	start := gf.AddTask(new(StartTask))
+	recordForecasts := gf.AddTask(&RecordForecasts{history: new([]string)})
+	end := gf.AddTask(new(EndTask))
+
+	// add task paths to the graphflow
+	gf.AddPath(start, ALWAYS, recordForecasts)
+	gf.AddPath(recordForecasts, ALWAYS, end)
+
+	return gf
+}
+
+func TestCloneKeepsNestedTaskGroups(t *testing.T) {
+	gf := buildGraphflow()
+	forecasting := gf.NewTaskGroup("Forecasting")
+	forecasting.SetColor("lightblue")
+	forecasting.NewTaskGroup("Rain").AddTasks(gf.Task("forecast-rain"))
+
+	clone := gf.Clone()
+
+	assert.Len(t, clone.TaskGroups(), 2)
+	assert.Equal(t, "lightblue", clone.TaskGroups()[0].Color())
+	assert.Same(t, clone.TaskGroups()[0], clone.TaskGroups()[1].Parent())
+	assert.Equal(t, []TaskIntf{clone.Task("forecast-rain")}, clone.TaskGroups()[1].Tasks())
+}
+
+func TestClone(t *testing.T) {
+	gf := buildGraphflow()
+	gf.NewTaskGroup("Forecasting").AddTasks(gf.Task("forecast-rain"), gf.Task("forecast-sun"))
+
+	clone := gf.Clone()
+
+	assert.Len(t, clone.Tasks(), len(gf.Tasks()))
+	for i, task := range gf.Tasks() {
+		assert.NotSame(t, task, clone.Tasks()[i])
+		assert.Equal(t, gf.TaskID(task), clone.TaskID(clone.Tasks()[i]))
+	}
+	assert.Same(t, clone.Task("forecast-rain"), clone.Paths()[clone.Task("is-the-sky-cloudy")][YES])
+	assert.Equal(t, []TaskIntf{clone.Task("forecast-rain"), clone.Task("forecast-sun")}, clone.TaskGroups()[0].Tasks())
+
+	// running one copy doesn't affect the other
+	ctx := new(ExecutionContext)
+	ctx.Set("Sky", "Cloudy")
+	err := clone.Run(ctx)
+
+	assert.Nil(t, err)
+	assert.Equal(t, YES, clone.Task("is-the-sky-cloudy").ExitPath())
+	assert.Equal(t, ALWAYS, gf.Task("is-the-sky-cloudy").ExitPath())
+	assert.Nil(t, gf.Result())
+}
+
+func TestCloneUsesCloner(t *testing.T) {
+	gf := buildRecordingGraphflow()
+	clone := gf.Clone()
+
+	ctx := new(ExecutionContext)
+	ctx.Set("Forecast", "Sun")
+	err := clone.Run(ctx)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Sun"}, *clone.Task("record-forecasts").(*RecordForecasts).history)
+	assert.Empty(t, *gf.Task("record-forecasts").(*RecordForecasts).history)
+}
+
+func TestCompose(t *testing.T) {
+	ctx := new(ExecutionContext)
+	ctx.Set("Sky", "Clear")
+
+	gf, err := Compose(buildGraphflow(), buildRecordingGraphflow())
+
+	assert.Nil(t, err)
+	assert.Len(t, gf.Tasks(), 6)
+	assert.Same(t, gf.Task("record-forecasts"), gf.Paths()[gf.Task("forecast-sun")][ALWAYS])
+
+	err = gf.Run(ctx)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Sun"}, *gf.Task("record-forecasts").(*RecordForecasts).history)
+	assert.True(t, gf.Result().Ended)
+}
+
+func TestComposeWithoutDefaultEndTaskThrowsError(t *testing.T) {
+	_, err := Compose(buildOutcomeGraphflow(), buildRecordingGraphflow())
+
+	assert.NotNil(t, err)
+}
+
+func TestSplice(t *testing.T) {
+	ctx := new(ExecutionContext)
+	ctx.Set("Sky", "Cloudy")
+
+	gf := buildGraphflow()
+	forecastRain := gf.Task("forecast-rain")
+
+	err := gf.Splice(forecastRain, ALWAYS, buildRecordingGraphflow())
+
+	assert.Nil(t, err)
+	assert.Len(t, gf.Tasks(), 6)
+	recordForecasts := gf.Task("record-forecasts")
+	assert.Same(t, recordForecasts, gf.Paths()[forecastRain][ALWAYS])
+	assert.Same(t, gf.Task("end"), gf.Paths()[recordForecasts][ALWAYS])
+
+	// splicing the same graphflow again gets fresh Tasks with new IDs
+	err = gf.Splice(gf.Task("forecast-sun"), ALWAYS, buildRecordingGraphflow())
+
+	assert.Nil(t, err)
+	assert.NotNil(t, gf.Task("record-forecasts-2"))
+
+	err = gf.Run(ctx)
+
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"Rain"}, *recordForecasts.(*RecordForecasts).history)
+
+	err = gf.Splice(forecastRain, YES, buildRecordingGraphflow())
+
+	assert.NotNil(t, err)
+}
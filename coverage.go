@@ -0,0 +1,176 @@
+package graphflow
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Coverage collects the Tasks executed and the Paths followed over many runs of a graphflow, eg by a table of test
+// cases, so the Tasks and Paths that were never exercised can be found
+type Coverage struct {
+	gf    *Graphflow
+	runs  int
+	tasks map[string]int
+	paths map[pathKey]int
+}
+
+// CoverageReport is the coverage collected by a Coverage. It can be marshalled to JSON.
+type CoverageReport struct {
+	// Runs is the number of runs the coverage was collected from
+	Runs int `json:"runs"`
+	// Tasks holds every Task in the graphflow, in the order they were added
+	Tasks []TaskCoverage `json:"tasks"`
+	// Paths holds every Path in the graphflow, in the order they were added
+	Paths []PathCoverage `json:"paths"`
+}
+
+// TaskCoverage is the number of times a Task was executed
+type TaskCoverage struct {
+	TaskID string `json:"task"`
+	Hits   int    `json:"hits"`
+}
+
+// PathCoverage is the number of times a Path was followed
+type PathCoverage struct {
+	From      string `json:"from"`
+	Condition string `json:"condition"`
+	To        string `json:"to"`
+	Hits      int    `json:"hits"`
+}
+
+// NewCoverage creates a Coverage for runs of the graphflow
+func NewCoverage(gf *Graphflow) *Coverage {
+	return &Coverage{
+		gf:    gf,
+		tasks: make(map[string]int),
+		paths: make(map[pathKey]int),
+	}
+}
+
+// CollectCoverage adds the result of every later Run or RunFrom of the graphflow to the Coverage, whether or not
+// the run fails. Passing nil stops collecting. Runs continued with Resume aren't added. A run that can't be added,
+// because the Coverage is of a different graphflow, returns the error from Add.
+func (gf *Graphflow) CollectCoverage(coverage *Coverage) {
+	gf.coverage = coverage
+}
+
+// Add adds a run of the graphflow to the Coverage. The Task that failed, if the run stopped with an error, counts as
+// executed.
+func (c *Coverage) Add(result *RunResult) error {
+	if result == nil {
+		return errors.New("Coverage can only be added from a RunResult")
+	}
+	if result.Hash != c.gf.Hash() {
+		return fmt.Errorf("Run of version \"%s\" is of a graphflow with a different hash", result.Version)
+	}
+	c.runs++
+	for i, step := range result.Steps {
+		task := c.gf.Task(step.TaskID)
+		c.tasks[step.TaskID]++
+		// the last Step only leads somewhere if the run went on to fail
+		if task != nil && (i < len(result.Steps)-1 || result.Next != "") {
+			if _, exists := c.gf.paths[task][step.ExitPath]; exists {
+				c.paths[pathKey{from: task, condition: step.ExitPath}]++
+			}
+		}
+	}
+	if result.Next != "" {
+		c.tasks[result.Next]++
+	}
+	return nil
+}
+
+// Report returns the coverage collected so far
+func (c *Coverage) Report() *CoverageReport {
+	report := &CoverageReport{
+		Runs:  c.runs,
+		Tasks: []TaskCoverage{},
+		Paths: []PathCoverage{},
+	}
+	for _, task := range c.gf.tasks {
+		id := c.gf.ids[task]
+		report.Tasks = append(report.Tasks, TaskCoverage{TaskID: id, Hits: c.tasks[id]})
+	}
+	for _, p := range c.gf.OrderedPaths() {
+		report.Paths = append(report.Paths, PathCoverage{
+			From:      c.gf.ids[p.From],
+			Condition: PathConditionName[p.Condition],
+			To:        c.gf.ids[p.To],
+			Hits:      c.paths[pathKey{from: p.From, condition: p.Condition}],
+		})
+	}
+	return report
+}
+
+// UncoveredTasks returns the IDs of the Tasks that were never executed
+func (r *CoverageReport) UncoveredTasks() []string {
+	ids := []string{}
+	for _, t := range r.Tasks {
+		if t.Hits == 0 {
+			ids = append(ids, t.TaskID)
+		}
+	}
+	return ids
+}
+
+// UncoveredPaths returns the Paths that were never followed
+func (r *CoverageReport) UncoveredPaths() []PathCoverage {
+	paths := []PathCoverage{}
+	for _, p := range r.Paths {
+		if p.Hits == 0 {
+			paths = append(paths, p)
+		}
+	}
+	return paths
+}
+
+// TaskPercent returns the percentage of Tasks that were executed
+func (r *CoverageReport) TaskPercent() float64 {
+	return percent(len(r.Tasks)-len(r.UncoveredTasks()), len(r.Tasks))
+}
+
+// PathPercent returns the percentage of Paths that were followed
+func (r *CoverageReport) PathPercent() float64 {
+	return percent(len(r.Paths)-len(r.UncoveredPaths()), len(r.Paths))
+}
+
+// String returns a text report listing how many times each Task was executed and each Path was followed, eg
+//
+//	Paths: 4/5 (80.0%)
+//	     2  is-the-sky-cloudy -YES-> forecast-rain
+//	     0  is-the-sky-cloudy -NO-> forecast-sun
+func (r *CoverageReport) String() string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "Coverage of %d runs\n", r.Runs)
+	fmt.Fprintf(&sb, "Tasks: %d/%d (%.1f%%)\n", len(r.Tasks)-len(r.UncoveredTasks()), len(r.Tasks), r.TaskPercent())
+	for _, t := range r.Tasks {
+		fmt.Fprintf(&sb, "%6d  %s\n", t.Hits, t.TaskID)
+	}
+	fmt.Fprintf(&sb, "Paths: %d/%d (%.1f%%)\n", len(r.Paths)-len(r.UncoveredPaths()), len(r.Paths), r.PathPercent())
+	for _, p := range r.Paths {
+		arrow := "->"
+		if p.Condition != PathConditionName[ALWAYS] {
+			arrow = fmt.Sprintf("-%s->", p.Condition)
+		}
+		fmt.Fprintf(&sb, "%6d  %s %s %s\n", p.Hits, p.From, arrow, p.To)
+	}
+	return sb.String()
+}
+
+// WriteJSON writes the report as indented JSON, for other tools to read
+func (r *CoverageReport) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(r)
+}
+
+// percent returns n as a percentage of total, which is 100 if there's nothing to cover
+func percent(n int, total int) float64 {
+	if total == 0 {
+		return 100
+	}
+	return float64(n) * 100 / float64(total)
+}
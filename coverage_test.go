@@ -0,0 +1,116 @@
+package graphflow
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCoverage(t *testing.T) {
+	gf := buildGraphflow()
+	coverage := NewCoverage(gf)
+	gf.CollectCoverage(coverage)
+
+	for _, sky := range []string{"Cloudy", "Cloudy"} {
+		ctx := new(ExecutionContext)
+		ctx.Set("Sky", sky)
+		assert.Nil(t, gf.Run(ctx))
+	}
+	report := coverage.Report()
+
+	assert.Equal(t, 2, report.Runs)
+	assert.Equal(t, []string{"forecast-sun"}, report.UncoveredTasks())
+	assert.Equal(t, []PathCoverage{
+		{From: "is-the-sky-cloudy", Condition: "NO", To: "forecast-sun"},
+		{From: "forecast-sun", Condition: "ALWAYS", To: "end"},
+	}, report.UncoveredPaths())
+	assert.Equal(t, 80.0, report.TaskPercent())
+	assert.Equal(t, 60.0, report.PathPercent())
+	assert.Equal(t, `Coverage of 2 runs
+Tasks: 4/5 (80.0%)
+     2  start
+     2  is-the-sky-cloudy
+     2  forecast-rain
+     0  forecast-sun
+     2  end
+Paths: 3/5 (60.0%)
+     2  start -> is-the-sky-cloudy
+     2  is-the-sky-cloudy -YES-> forecast-rain
+     0  is-the-sky-cloudy -NO-> forecast-sun
+     2  forecast-rain -> end
+     0  forecast-sun -> end
+`, report.String())
+
+	gf.CollectCoverage(nil)
+	ctx := new(ExecutionContext)
+	ctx.Set("Sky", "Clear")
+	assert.Nil(t, gf.Run(ctx))
+
+	assert.Equal(t, 2, coverage.Report().Runs)
+	assert.Nil(t, coverage.Add(gf.Result()))
+	assert.Empty(t, coverage.Report().UncoveredTasks())
+	assert.Empty(t, coverage.Report().UncoveredPaths())
+}
+
+func TestCoverageCountsFailedTask(t *testing.T) {
+	ctx := new(ExecutionContext)
+	ctx.Set("Sky", "Clear")
+	gf := buildGraphflow()
+	gf.InsertBetween(gf.Task("forecast-sun"), ALWAYS, new(FailOnce))
+	coverage := NewCoverage(gf)
+	gf.CollectCoverage(coverage)
+
+	assert.NotNil(t, gf.Run(ctx))
+
+	report := coverage.Report()
+	assert.Equal(t, TaskCoverage{TaskID: "fail-once", Hits: 1}, report.Tasks[5])
+	assert.Equal(t, PathCoverage{From: "forecast-sun", Condition: "ALWAYS", To: "fail-once", Hits: 1}, report.Paths[4])
+	assert.Equal(t, 0, report.Paths[5].Hits)
+}
+
+func TestCoverageOfDifferentGraphflowThrowsError(t *testing.T) {
+	ctx := new(ExecutionContext)
+	ctx.Set("Sky", "Clear")
+	gf := buildGraphflow()
+	assert.Nil(t, gf.Run(ctx))
+	coverage := NewCoverage(buildOutcomeGraphflow())
+
+	err := coverage.Add(gf.Result())
+
+	assert.NotNil(t, err)
+	assert.NotNil(t, coverage.Add(nil))
+	assert.Equal(t, 0, coverage.Report().Runs)
+}
+
+func TestCollectingCoverageOfDifferentGraphflowThrowsError(t *testing.T) {
+	ctx := new(ExecutionContext)
+	ctx.Set("Sky", "Clear")
+	gf := buildGraphflow()
+	coverage := NewCoverage(buildOutcomeGraphflow())
+	gf.CollectCoverage(coverage)
+
+	err := gf.Run(ctx)
+
+	assert.EqualError(t, err, "Run of version \"\" is of a graphflow with a different hash")
+	assert.True(t, gf.Result().Ended)
+	assert.Equal(t, 0, coverage.Report().Runs)
+}
+
+func TestCoverageReportJSON(t *testing.T) {
+	gf := buildGraphflow()
+	coverage := NewCoverage(gf)
+
+	var buf bytes.Buffer
+	err := coverage.Report().WriteJSON(&buf)
+
+	assert.Nil(t, err)
+	assert.Contains(t, buf.String(), `"from": "is-the-sky-cloudy",
+      "condition": "YES",
+      "to": "forecast-rain",
+      "hits": 0`)
+	var report CoverageReport
+	assert.Nil(t, json.Unmarshal(buf.Bytes(), &report))
+	assert.Equal(t, coverage.Report(), &report)
+}
@@ -0,0 +1,455 @@
+package graphflow
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"sort"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Definition is a declarative description of a graphflow that can be stored as YAML or JSON, allowing
+// a workflow to be edited without Go changes. Tasks are created from their type names by a Registry.
+//
+// Example:
+//
+//	tasks:
+//	  - id: start
+//	    type: Start
+//	  - id: is-the-sky-cloudy
+//	    type: IsTheSkyCloudy
+//	  - id: forecast
+//	    type: Forecast
+//	    params:
+//	      weather: Rain
+//	    metadata:
+//	      owner: Weather Team
+//	      tags: [forecasting]
+//	      sla: 5s
+//	  - id: end
+//	    type: End
+//	paths:
+//	  - from: start
+//	    to: is-the-sky-cloudy
+//	  - from: is-the-sky-cloudy
+//	    condition: "YES"
+//	    to: forecast
+//	groups:
+//	  - name: Forecasting
+//	    tasks: [forecast]
+//	  - name: Rain
+//	    parent: Forecasting
+//	    color: lightblue
+//	    errorHandler: end
+//	    retries: 2
+//	    retryDelay: 1s
+//	    timeout: 30s
+type Definition struct {
+	Tasks  []TaskDefinition  `json:"tasks" yaml:"tasks"`
+	Paths  []PathDefinition  `json:"paths,omitempty" yaml:"paths,omitempty"`
+	Groups []GroupDefinition `json:"groups,omitempty" yaml:"groups,omitempty"`
+}
+
+// TaskDefinition describes a single Task by its ID, its type name in the Registry and the parameters
+// passed to that type's TaskConstructor, along with any Metadata documenting it
+type TaskDefinition struct {
+	ID       string                 `json:"id" yaml:"id"`
+	Type     string                 `json:"type" yaml:"type"`
+	Params   map[string]interface{} `json:"params,omitempty" yaml:"params,omitempty"`
+	Metadata *MetadataDefinition    `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+}
+
+// MetadataDefinition describes the Metadata of a Task, with its SLA as a duration such as "30s"
+type MetadataDefinition struct {
+	Description string   `json:"description,omitempty" yaml:"description,omitempty"`
+	Owner       string   `json:"owner,omitempty" yaml:"owner,omitempty"`
+	Tags        []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+	DocURL      string   `json:"docURL,omitempty" yaml:"docURL,omitempty"`
+	SLA         string   `json:"sla,omitempty" yaml:"sla,omitempty"`
+}
+
+// PathDefinition describes a Path between two Tasks by their IDs. An empty Condition is treated as ALWAYS.
+type PathDefinition struct {
+	From      string `json:"from" yaml:"from"`
+	Condition string `json:"condition,omitempty" yaml:"condition,omitempty"`
+	To        string `json:"to" yaml:"to"`
+}
+
+// GroupDefinition describes a TaskGroup and the IDs of the Tasks in it. Parent names the TaskGroup it's nested
+// inside, which has to be defined before it. ErrorHandler is the ID of its error handler Task, and RetryDelay and
+// Timeout are durations such as "500ms" or "1m".
+type GroupDefinition struct {
+	Name         string   `json:"name" yaml:"name"`
+	Parent       string   `json:"parent,omitempty" yaml:"parent,omitempty"`
+	Color        string   `json:"color,omitempty" yaml:"color,omitempty"`
+	Description  string   `json:"description,omitempty" yaml:"description,omitempty"`
+	ErrorHandler string   `json:"errorHandler,omitempty" yaml:"errorHandler,omitempty"`
+	Retries      int      `json:"retries,omitempty" yaml:"retries,omitempty"`
+	RetryDelay   string   `json:"retryDelay,omitempty" yaml:"retryDelay,omitempty"`
+	Timeout      string   `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	Tasks        []string `json:"tasks,omitempty" yaml:"tasks,omitempty"`
+}
+
+// groupDefinition describes a TaskGroup of the graphflow
+func (gf *Graphflow) groupDefinition(taskGroup *TaskGroup) GroupDefinition {
+	gd := GroupDefinition{
+		Name:        taskGroup.name,
+		Color:       taskGroup.color,
+		Description: taskGroup.description,
+	}
+	if taskGroup.parent != nil {
+		gd.Parent = taskGroup.parent.name
+	}
+	if taskGroup.errorHandler != nil {
+		gd.ErrorHandler = gf.ids[taskGroup.errorHandler]
+	}
+	gd.Retries = taskGroup.policy.Retries
+	if taskGroup.policy.RetryDelay > 0 {
+		gd.RetryDelay = taskGroup.policy.RetryDelay.String()
+	}
+	if taskGroup.policy.Timeout > 0 {
+		gd.Timeout = taskGroup.policy.Timeout.String()
+	}
+	for _, task := range taskGroup.tasks {
+		gd.Tasks = append(gd.Tasks, gf.ids[task])
+	}
+	return gd
+}
+
+// TaskConstructor creates a new Task from the parameters given for it in a Definition. Parameters are
+// decoded from YAML or JSON, so numbers may be ints or float64s depending on the format.
+type TaskConstructor func(params map[string]interface{}) (TaskIntf, error)
+
+// ParamsProvider can be implemented by Tasks that were created with parameters, so they can be written
+// back out by the Registry when a graphflow is exported
+type ParamsProvider interface {
+	Params() map[string]interface{}
+}
+
+// Registry maps the Task type names used in a Definition to the constructors that create them.
+// The StartTask and EndTask types are always registered as "Start" and "End", taking an optional
+// "entry" and "outcome" parameter respectively.
+type Registry struct {
+	constructors map[string]TaskConstructor
+	// typeNames holds the type name of each Go type registered with a prototype, or "" if more than one
+	// type name was registered for it
+	typeNames map[reflect.Type]string
+}
+
+// NewRegistry creates a Registry containing the "Start" and "End" Task types
+func NewRegistry() *Registry {
+	r := &Registry{
+		constructors: make(map[string]TaskConstructor),
+		typeNames:    make(map[reflect.Type]string),
+	}
+	r.RegisterType("Start", new(StartTask), func(params map[string]interface{}) (TaskIntf, error) {
+		entry, err := stringParam(params, "entry")
+		if err != nil {
+			return nil, err
+		}
+		return NewStartTask(entry), nil
+	})
+	r.RegisterType("End", new(EndTask), func(params map[string]interface{}) (TaskIntf, error) {
+		outcome, err := stringParam(params, "outcome")
+		if err != nil {
+			return nil, err
+		}
+		return NewEndTask(outcome), nil
+	})
+	return r
+}
+
+// Register adds a Task type to the Registry, replacing any existing type with the same name. Only the
+// Tasks of graphflows built from a Definition can be exported as this type; use RegisterType for Tasks
+// created in Go.
+func (r *Registry) Register(typeName string, constructor TaskConstructor) {
+	r.constructors[typeName] = constructor
+}
+
+// RegisterType adds a Task type to the Registry like Register, with a prototype Task whose Go type
+// identifies the Tasks created in Go that are of this type when a graphflow is exported. Each Go type
+// should only be registered under one type name, and ActionFunc and QuestionFunc Tasks can't be used as
+// prototypes, as every one of them is of the same Go type.
+func (r *Registry) RegisterType(typeName string, prototype TaskIntf, constructor TaskConstructor) {
+	r.Register(typeName, constructor)
+	if isFuncTask(prototype) {
+		return
+	}
+	t := reflect.TypeOf(prototype)
+	if existing, exists := r.typeNames[t]; exists && existing != typeName {
+		r.typeNames[t] = ""
+		return
+	}
+	r.typeNames[t] = typeName
+}
+
+// New creates a Task of the named type with the given parameters
+func (r *Registry) New(typeName string, params map[string]interface{}) (TaskIntf, error) {
+	constructor, exists := r.constructors[typeName]
+	if !exists {
+		return nil, fmt.Errorf("Task type \"%s\" isn't in the Registry", typeName)
+	}
+	task, err := constructor(params)
+	if err != nil {
+		return nil, fmt.Errorf("Failed to create Task of type \"%s\": %w", typeName, err)
+	}
+	return task, nil
+}
+
+// Build creates a graphflow from a Definition, returning an error if the Definition refers to
+// unknown Task types or IDs, or if the resulting graphflow isn't valid
+func (r *Registry) Build(def *Definition) (*Graphflow, error) {
+	gf := new(Graphflow)
+	tasks := make(map[string]TaskIntf)
+	for _, td := range def.Tasks {
+		if td.ID == "" {
+			return nil, fmt.Errorf("Task of type \"%s\" needs an id", td.Type)
+		}
+		if _, exists := tasks[td.ID]; exists {
+			return nil, fmt.Errorf("Task id \"%s\" is used more than once", td.ID)
+		}
+		task, err := r.New(td.Type, td.Params)
+		if err != nil {
+			return nil, err
+		}
+		tasks[td.ID] = gf.AddTaskWithID(td.ID, task)
+		gf.setTypeName(task, td.Type)
+		if md := td.Metadata; md != nil {
+			sla, err := parseDuration(md.SLA)
+			if err != nil {
+				return nil, fmt.Errorf("Task \"%s\" has an invalid sla: %s", td.ID, err)
+			}
+			gf.SetMetadata(task, Metadata{
+				Description: md.Description,
+				Owner:       md.Owner,
+				Tags:        md.Tags,
+				DocURL:      md.DocURL,
+				SLA:         sla,
+			})
+		}
+	}
+	lookup := func(id string) (TaskIntf, error) {
+		task, exists := tasks[id]
+		if !exists {
+			return nil, fmt.Errorf("There is no Task with the id \"%s\"", id)
+		}
+		return task, nil
+	}
+	for _, pd := range def.Paths {
+		from, err := lookup(pd.From)
+		if err != nil {
+			return nil, err
+		}
+		to, err := lookup(pd.To)
+		if err != nil {
+			return nil, err
+		}
+		condition, err := ParsePathCondition(pd.Condition)
+		if err != nil {
+			return nil, err
+		}
+		gf.AddPath(from, condition, to)
+	}
+	taskGroups := make(map[string]*TaskGroup)
+	for _, gd := range def.Groups {
+		var taskGroup *TaskGroup
+		if gd.Parent == "" {
+			taskGroup = gf.NewTaskGroup(gd.Name)
+		} else if parent, exists := taskGroups[gd.Parent]; exists {
+			taskGroup = parent.NewTaskGroup(gd.Name)
+		} else {
+			return nil, fmt.Errorf("Group \"%s\" is nested in \"%s\" which hasn't been defined before it", gd.Name, gd.Parent)
+		}
+		taskGroup.SetColor(gd.Color)
+		taskGroup.SetDescription(gd.Description)
+		if gd.ErrorHandler != "" {
+			handler, err := lookup(gd.ErrorHandler)
+			if err != nil {
+				return nil, err
+			}
+			taskGroup.SetErrorHandler(handler)
+		}
+		retryDelay, err := parseDuration(gd.RetryDelay)
+		if err != nil {
+			return nil, fmt.Errorf("Group \"%s\" has an invalid retryDelay: %s", gd.Name, err)
+		}
+		timeout, err := parseDuration(gd.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("Group \"%s\" has an invalid timeout: %s", gd.Name, err)
+		}
+		taskGroup.SetPolicy(Policy{Retries: gd.Retries, RetryDelay: retryDelay, Timeout: timeout})
+		taskGroups[gd.Name] = taskGroup
+		for _, id := range gd.Tasks {
+			task, err := lookup(id)
+			if err != nil {
+				return nil, err
+			}
+			taskGroup.AddTasks(task)
+		}
+	}
+	if err := gf.validateTasks(); err != nil {
+		return nil, err
+	}
+	return gf, nil
+}
+
+// Define creates a Definition describing an existing graphflow. Every Task in the graphflow needs to have
+// been built from a Definition, or be of a Go type registered with RegisterType, and Tasks with parameters
+// should implement ParamsProvider. The type names Tasks were built with are held by the graphflow, so the
+// Registry doesn't keep hold of any Tasks.
+func (r *Registry) Define(gf *Graphflow) (*Definition, error) {
+	def := new(Definition)
+	ids := gf.ids
+	for _, task := range gf.tasks {
+		typeName, err := r.typeName(gf, task)
+		if err != nil {
+			return nil, err
+		}
+		td := TaskDefinition{
+			ID:   ids[task],
+			Type: typeName,
+		}
+		if p, ok := task.(ParamsProvider); ok {
+			td.Params = p.Params()
+		}
+		if metadata := gf.Metadata(task); !metadata.IsZero() {
+			td.Metadata = &MetadataDefinition{
+				Description: metadata.Description,
+				Owner:       metadata.Owner,
+				Tags:        metadata.Tags,
+				DocURL:      metadata.DocURL,
+			}
+			if metadata.SLA > 0 {
+				td.Metadata.SLA = metadata.SLA.String()
+			}
+		}
+		def.Tasks = append(def.Tasks, td)
+	}
+	for _, from := range gf.tasks {
+		conditions := []PathCondition{}
+		for condition := range gf.paths[from] {
+			conditions = append(conditions, condition)
+		}
+		sort.Slice(conditions, func(i, j int) bool { return conditions[i] < conditions[j] })
+		for _, condition := range conditions {
+			to := gf.paths[from][condition]
+			if _, exists := ids[to]; !exists {
+				return nil, fmt.Errorf("Task %s has a path to %s which hasn't been added to the graphflow", from, to)
+			}
+			pd := PathDefinition{
+				From: ids[from],
+				To:   ids[to],
+			}
+			if condition != ALWAYS {
+				pd.Condition = PathConditionName[condition]
+			}
+			def.Paths = append(def.Paths, pd)
+		}
+	}
+	for _, taskGroup := range gf.taskGroups {
+		def.Groups = append(def.Groups, gf.groupDefinition(taskGroup))
+	}
+	return def, nil
+}
+
+// LoadYAML reads a YAML Definition and builds a graphflow from it
+func (r *Registry) LoadYAML(reader io.Reader) (*Graphflow, error) {
+	def := new(Definition)
+	if err := yaml.NewDecoder(reader).Decode(def); err != nil {
+		return nil, err
+	}
+	return r.Build(def)
+}
+
+// LoadJSON reads a JSON Definition and builds a graphflow from it
+func (r *Registry) LoadJSON(reader io.Reader) (*Graphflow, error) {
+	def := new(Definition)
+	if err := json.NewDecoder(reader).Decode(def); err != nil {
+		return nil, err
+	}
+	return r.Build(def)
+}
+
+// WriteYAML writes a YAML Definition of the graphflow
+func (r *Registry) WriteYAML(w io.Writer, gf *Graphflow) error {
+	def, err := r.Define(gf)
+	if err != nil {
+		return err
+	}
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(def); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+// WriteJSON writes a JSON Definition of the graphflow
+func (r *Registry) WriteJSON(w io.Writer, gf *Graphflow) error {
+	def, err := r.Define(gf)
+	if err != nil {
+		return err
+	}
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(def)
+}
+
+// typeName returns the type name of a Task in a graphflow: the one it was built with by Build, or else
+// the one its Go type was registered with
+func (r *Registry) typeName(gf *Graphflow, task TaskIntf) (string, error) {
+	if typeName, exists := gf.typeNames[task]; exists {
+		return typeName, nil
+	}
+	if isFuncTask(task) {
+		return "", fmt.Errorf("Task %s is an ActionFunc or QuestionFunc, which can only be defined if it was built from a Definition", task)
+	}
+	typeName, exists := r.typeNames[reflect.TypeOf(task)]
+	if !exists {
+		return "", fmt.Errorf("Task %s is of type %T which isn't in the Registry", task, task)
+	}
+	if typeName == "" {
+		return "", fmt.Errorf("Task %s is of type %T which is registered under more than one type name", task, task)
+	}
+	return typeName, nil
+}
+
+// setTypeName records the type name a Task in the graphflow was built with
+func (gf *Graphflow) setTypeName(task TaskIntf, typeName string) {
+	if gf.typeNames == nil {
+		gf.typeNames = make(map[TaskIntf]string)
+	}
+	gf.typeNames[task] = typeName
+}
+
+// isFuncTask returns true if a Task was created by ActionFunc or QuestionFunc
+func isFuncTask(task TaskIntf) bool {
+	switch task.(type) {
+	case *actionTask, *questionTask:
+		return true
+	}
+	return false
+}
+
+func stringParam(params map[string]interface{}, key string) (string, error) {
+	value, exists := params[key]
+	if !exists || value == nil {
+		return "", nil
+	}
+	s, ok := value.(string)
+	if !ok {
+		return "", fmt.Errorf("Parameter \"%s\" needs to be a string", key)
+	}
+	return s, nil
+}
+
+// parseDuration parses a duration written in a Definition, treating an empty string as zero
+func parseDuration(s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(s)
+}
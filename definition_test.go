@@ -0,0 +1,266 @@
+package graphflow
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// Forecast is a Task struct created with a "weather" parameter
+type Forecast struct {
+	Task
+	weather string
+}
+
+// String returns a description of the Task
+func (t *Forecast) String() string {
+	return fmt.Sprintf("Forecast %s", t.weather)
+}
+
+// Execute sets the ExecutionContext's Forecast value to the Task's weather
+func (t *Forecast) Execute(ctx *ExecutionContext) error {
+	ctx.Set("Forecast", t.weather)
+	return nil
+}
+
+// Params returns the parameters the Task was created with
+func (t *Forecast) Params() map[string]interface{} {
+	return map[string]interface{}{"weather": t.weather}
+}
+
+func newTestRegistry() *Registry {
+	registry := NewRegistry()
+	registry.RegisterType("IsTheSkyCloudy", new(IsTheSkyCloudy), func(params map[string]interface{}) (TaskIntf, error) {
+		return new(IsTheSkyCloudy), nil
+	})
+	registry.Register("Forecast", func(params map[string]interface{}) (TaskIntf, error) {
+		weather, err := stringParam(params, "weather")
+		if err != nil {
+			return nil, err
+		}
+		if weather == "" {
+			return nil, fmt.Errorf("Forecast needs a weather parameter")
+		}
+		return &Forecast{weather: weather}, nil
+	})
+	return registry
+}
+
+const forecastYAML = `
+tasks:
+  - id: start
+    type: Start
+  - id: is-the-sky-cloudy
+    type: IsTheSkyCloudy
+  - id: forecast-rain
+    type: Forecast
+    params:
+      weather: Rain
+  - id: forecast-sun
+    type: Forecast
+    params:
+      weather: Sun
+  - id: end
+    type: End
+paths:
+  - from: start
+    to: is-the-sky-cloudy
+  - from: is-the-sky-cloudy
+    condition: "YES"
+    to: forecast-rain
+  - from: is-the-sky-cloudy
+    condition: "NO"
+    to: forecast-sun
+  - from: forecast-rain
+    to: end
+  - from: forecast-sun
+    to: end
+groups:
+  - name: Forecasting
+    tasks:
+      - forecast-rain
+      - forecast-sun
+`
+
+func TestLoadYAML(t *testing.T) {
+	ctx := new(ExecutionContext)
+	ctx.Set("Sky", "Cloudy")
+
+	gf, err := newTestRegistry().LoadYAML(strings.NewReader(forecastYAML))
+
+	assert.Nil(t, err)
+	assert.Len(t, gf.Tasks(), 5)
+	assert.Len(t, gf.TaskGroups(), 1)
+	assert.Len(t, gf.TaskGroups()[0].Tasks(), 2)
+
+	err = gf.Run(ctx)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Rain", ctx.Get("Forecast"))
+}
+
+func TestWriteYAMLRoundTrip(t *testing.T) {
+	registry := newTestRegistry()
+	gf, err := registry.LoadYAML(strings.NewReader(forecastYAML))
+	assert.Nil(t, err)
+
+	var buf bytes.Buffer
+	err = registry.WriteYAML(&buf, gf)
+
+	assert.Nil(t, err)
+	assert.Equal(t, strings.TrimPrefix(forecastYAML, "\n"), buf.String())
+}
+
+func TestWriteJSONRoundTrip(t *testing.T) {
+	registry := newTestRegistry()
+	gf, err := registry.LoadYAML(strings.NewReader(forecastYAML))
+	assert.Nil(t, err)
+
+	var buf bytes.Buffer
+	err = registry.WriteJSON(&buf, gf)
+	assert.Nil(t, err)
+
+	loaded, err := registry.LoadJSON(&buf)
+	assert.Nil(t, err)
+
+	expected, _ := registry.Define(gf)
+	actual, _ := registry.Define(loaded)
+	assert.Equal(t, expected, actual)
+}
+
+func TestDefineGraphflowBuiltInGo(t *testing.T) {
+	gf := buildOutcomeGraphflow()
+
+	def, err := newTestRegistry().Define(gf)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "end-take-umbrella", def.Tasks[2].ID)
+	assert.Equal(t, "End", def.Tasks[2].Type)
+	assert.Equal(t, map[string]interface{}{"outcome": "Take Umbrella"}, def.Tasks[2].Params)
+	assert.Equal(t, PathDefinition{From: "is-the-sky-cloudy", Condition: "YES", To: "end-take-umbrella"}, def.Paths[1])
+}
+
+func TestRegisterDoesntCallTheConstructor(t *testing.T) {
+	registry := NewRegistry()
+	calls := 0
+
+	registry.Register("Counted", func(params map[string]interface{}) (TaskIntf, error) {
+		calls++
+		return new(IsTheSkyCloudy), nil
+	})
+
+	assert.Equal(t, 0, calls)
+}
+
+func TestDefineFuncTasksBuiltFromDefinition(t *testing.T) {
+	registry := NewRegistry()
+	for _, name := range []string{"Forecast Rain", "Forecast Sun"} {
+		name := name
+		registry.Register(name, func(params map[string]interface{}) (TaskIntf, error) {
+			return ActionFunc(name, func(ctx *ExecutionContext) error {
+				ctx.Set("Forecast", name)
+				return nil
+			}), nil
+		})
+	}
+	gf, err := registry.Build(&Definition{
+		Tasks: []TaskDefinition{
+			{ID: "start", Type: "Start"},
+			{ID: "forecast-sun", Type: "Forecast Sun"},
+			{ID: "forecast-rain", Type: "Forecast Rain"},
+			{ID: "end", Type: "End"},
+		},
+		Paths: []PathDefinition{
+			{From: "start", Condition: "ALWAYS", To: "forecast-sun"},
+			{From: "forecast-sun", Condition: "ALWAYS", To: "forecast-rain"},
+			{From: "forecast-rain", Condition: "ALWAYS", To: "end"},
+		},
+	})
+	assert.Nil(t, err)
+
+	def, err := registry.Define(gf.Clone())
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Forecast Sun", def.Tasks[1].Type)
+	assert.Equal(t, "Forecast Rain", def.Tasks[2].Type)
+}
+
+func TestDefineFuncTaskNotBuiltFromDefinitionThrowsError(t *testing.T) {
+	registry := newTestRegistry()
+	registry.RegisterType("Report", ActionFunc("Report", nil), func(params map[string]interface{}) (TaskIntf, error) {
+		return ActionFunc("Report", func(ctx *ExecutionContext) error { return nil }), nil
+	})
+	gf := buildOutcomeGraphflow()
+	gf.InsertBetween(gf.Task("start"), ALWAYS, ActionFunc("Report", func(ctx *ExecutionContext) error { return nil }))
+
+	_, err := registry.Define(gf)
+
+	assert.EqualError(t, err, "Task Report is an ActionFunc or QuestionFunc, which can only be defined if it was built from a Definition")
+}
+
+func TestDefineTypeRegisteredTwiceThrowsError(t *testing.T) {
+	registry := newTestRegistry()
+	registry.RegisterType("IsItCloudy", new(IsTheSkyCloudy), func(params map[string]interface{}) (TaskIntf, error) {
+		return new(IsTheSkyCloudy), nil
+	})
+
+	_, err := registry.Define(buildOutcomeGraphflow())
+
+	assert.EqualError(t, err, "Task Is the sky cloudy? is of type *graphflow.IsTheSkyCloudy which is registered under more than one type name")
+}
+
+func TestDefineUnregisteredTaskThrowsError(t *testing.T) {
+	gf := buildGraphflow() // ForecastRain isn't registered
+
+	_, err := newTestRegistry().Define(gf)
+
+	assert.NotNil(t, err)
+}
+
+func TestLoadInvalidDefinitionsThrowsErrors(t *testing.T) {
+	registry := newTestRegistry()
+	invalid := map[string]string{
+		"unknown type":      `{"tasks": [{"id": "start", "type": "Begin"}]}`,
+		"missing id":        `{"tasks": [{"type": "Start"}]}`,
+		"duplicate id":      `{"tasks": [{"id": "start", "type": "Start"}, {"id": "start", "type": "End"}]}`,
+		"unknown path task": `{"tasks": [{"id": "start", "type": "Start"}, {"id": "end", "type": "End"}], "paths": [{"from": "start", "to": "finish"}]}`,
+		"unknown condition": `{"tasks": [{"id": "start", "type": "Start"}, {"id": "end", "type": "End"}], "paths": [{"from": "start", "condition": "MAYBE", "to": "end"}]}`,
+		"bad params":        `{"tasks": [{"id": "start", "type": "Start", "params": {"entry": 1}}, {"id": "end", "type": "End"}]}`,
+		"missing params":    `{"tasks": [{"id": "start", "type": "Start"}, {"id": "forecast", "type": "Forecast"}, {"id": "end", "type": "End"}]}`,
+		"invalid graph":     `{"tasks": [{"id": "start", "type": "Start"}]}`,
+		"unknown parent":    `{"tasks": [{"id": "start", "type": "Start"}, {"id": "end", "type": "End"}], "paths": [{"from": "start", "to": "end"}], "groups": [{"name": "Inner", "parent": "Outer"}]}`,
+	}
+	for name, def := range invalid {
+		_, err := registry.LoadJSON(strings.NewReader(def))
+		assert.NotNil(t, err, name)
+	}
+}
+
+func TestNestedGroupsRoundTrip(t *testing.T) {
+	registry := newTestRegistry()
+	gf, err := registry.LoadYAML(strings.NewReader(forecastYAML))
+	assert.Nil(t, err)
+	forecasting := gf.TaskGroups()[0]
+	forecasting.SetDescription("Decides what to forecast")
+	rain := forecasting.NewTaskGroup("Rain")
+	rain.SetColor("lightblue")
+
+	def, err := registry.Define(gf)
+
+	assert.Nil(t, err)
+	assert.Equal(t, GroupDefinition{Name: "Rain", Parent: "Forecasting", Color: "lightblue"}, def.Groups[1])
+
+	var buf bytes.Buffer
+	assert.Nil(t, registry.WriteJSON(&buf, gf))
+	loaded, err := registry.LoadJSON(&buf)
+
+	assert.Nil(t, err)
+	assert.Len(t, loaded.TaskGroups(), 2)
+	assert.Equal(t, "Decides what to forecast", loaded.TaskGroups()[0].Description())
+	assert.Same(t, loaded.TaskGroups()[0], loaded.TaskGroups()[1].Parent())
+	assert.Equal(t, "lightblue", loaded.TaskGroups()[1].Color())
+	assert.Equal(t, gf.Hash(), loaded.Hash())
+}
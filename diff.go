@@ -0,0 +1,150 @@
+package graphflow
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// GraphflowDiff is a structural comparison of two versions of a graphflow, with Tasks identified by their IDs
+type GraphflowDiff struct {
+	// AddedTasks holds the IDs of Tasks only in the new version
+	AddedTasks []string
+	// RemovedTasks holds the IDs of Tasks only in the old version
+	RemovedTasks []string
+	// AddedPaths holds the Paths only in the new version
+	AddedPaths []PathChange
+	// RemovedPaths holds the Paths only in the old version
+	RemovedPaths []PathChange
+	// RetargetedPaths holds the Paths that leave the same Task with the same PathCondition in both versions,
+	// but lead to a different Task
+	RetargetedPaths []PathChange
+	// GroupChanges holds the Tasks in both versions whose TaskGroup has changed
+	GroupChanges []GroupChange
+}
+
+// PathChange describes a Path reported by a GraphflowDiff. PreviousTo is only set for retargeted Paths.
+type PathChange struct {
+	From       string
+	Condition  PathCondition
+	To         string
+	PreviousTo string
+}
+
+// GroupChange describes a Task that has moved between TaskGroups. Groups are named by their QualifiedName, so a
+// Task moving between TaskGroups with the same name nested in different places is a change. An empty group name
+// means the Task wasn't in a TaskGroup.
+type GroupChange struct {
+	TaskID        string
+	PreviousGroup string
+	Group         string
+}
+
+// Diff compares an old and a new version of a graphflow, reporting the Tasks, Paths and TaskGroup memberships
+// that have changed between them
+func Diff(old *Graphflow, updated *Graphflow) *GraphflowDiff {
+	d := new(GraphflowDiff)
+	oldPaths := pathsByID(old)
+	newPaths := pathsByID(updated)
+	oldGroups := groupsByID(old)
+	newGroups := groupsByID(updated)
+	for _, task := range updated.tasks {
+		id := updated.ids[task]
+		if old.Task(id) == nil {
+			d.AddedTasks = append(d.AddedTasks, id)
+		} else if oldGroups[id] != newGroups[id] {
+			d.GroupChanges = append(d.GroupChanges, GroupChange{TaskID: id, PreviousGroup: oldGroups[id], Group: newGroups[id]})
+		}
+	}
+	for _, task := range old.tasks {
+		id := old.ids[task]
+		if updated.Task(id) == nil {
+			d.RemovedTasks = append(d.RemovedTasks, id)
+		}
+	}
+	for _, p := range newPaths {
+		previous, exists := findPath(oldPaths, p.From, p.Condition)
+		if !exists {
+			d.AddedPaths = append(d.AddedPaths, p)
+		} else if previous.To != p.To {
+			p.PreviousTo = previous.To
+			d.RetargetedPaths = append(d.RetargetedPaths, p)
+		}
+	}
+	for _, p := range oldPaths {
+		if _, exists := findPath(newPaths, p.From, p.Condition); !exists {
+			d.RemovedPaths = append(d.RemovedPaths, p)
+		}
+	}
+	return d
+}
+
+// Empty returns true if the GraphflowDiff found no changes
+func (d *GraphflowDiff) Empty() bool {
+	return len(d.AddedTasks) == 0 && len(d.RemovedTasks) == 0 && len(d.AddedPaths) == 0 &&
+		len(d.RemovedPaths) == 0 && len(d.RetargetedPaths) == 0 && len(d.GroupChanges) == 0
+}
+
+// String returns a line by line summary of the GraphflowDiff, suitable for reviewing a change to a graphflow
+func (d *GraphflowDiff) String() string {
+	var sb strings.Builder
+	for _, id := range d.AddedTasks {
+		fmt.Fprintf(&sb, "+ task %s\n", id)
+	}
+	for _, id := range d.RemovedTasks {
+		fmt.Fprintf(&sb, "- task %s\n", id)
+	}
+	for _, p := range d.AddedPaths {
+		fmt.Fprintf(&sb, "+ path %s %s -> %s\n", p.From, PathConditionName[p.Condition], p.To)
+	}
+	for _, p := range d.RemovedPaths {
+		fmt.Fprintf(&sb, "- path %s %s -> %s\n", p.From, PathConditionName[p.Condition], p.To)
+	}
+	for _, p := range d.RetargetedPaths {
+		fmt.Fprintf(&sb, "~ path %s %s -> %s (was %s)\n", p.From, PathConditionName[p.Condition], p.To, p.PreviousTo)
+	}
+	for _, g := range d.GroupChanges {
+		fmt.Fprintf(&sb, "~ group %s \"%s\" -> \"%s\"\n", g.TaskID, g.PreviousGroup, g.Group)
+	}
+	return sb.String()
+}
+
+// pathsByID lists the Paths of a graphflow by Task ID, ordered by the Task they leave and then their PathCondition
+func pathsByID(gf *Graphflow) []PathChange {
+	paths := []PathChange{}
+	for _, from := range gf.tasks {
+		conditions := []PathCondition{}
+		for condition := range gf.paths[from] {
+			conditions = append(conditions, condition)
+		}
+		sort.Slice(conditions, func(i, j int) bool { return conditions[i] < conditions[j] })
+		for _, condition := range conditions {
+			paths = append(paths, PathChange{
+				From:      gf.ids[from],
+				Condition: condition,
+				To:        gf.ids[gf.paths[from][condition]],
+			})
+		}
+	}
+	return paths
+}
+
+func findPath(paths []PathChange, from string, condition PathCondition) (PathChange, bool) {
+	for _, p := range paths {
+		if p.From == from && p.Condition == condition {
+			return p, true
+		}
+	}
+	return PathChange{}, false
+}
+
+// groupsByID maps the ID of each grouped Task to the qualified name of its TaskGroup
+func groupsByID(gf *Graphflow) map[string]string {
+	groups := make(map[string]string)
+	for _, taskGroup := range gf.taskGroups {
+		for _, task := range taskGroup.tasks {
+			groups[gf.ids[task]] = taskGroup.QualifiedName()
+		}
+	}
+	return groups
+}
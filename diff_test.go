@@ -0,0 +1,64 @@
+package graphflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffOfIdenticalGraphflowsIsEmpty(t *testing.T) {
+	d := Diff(buildGraphflow(), buildGraphflow())
+
+	assert.True(t, d.Empty())
+	assert.Equal(t, "", d.String())
+}
+
+func TestDiff(t *testing.T) {
+	old := buildGraphflow()
+	old.NewTaskGroup("Forecasting").AddTasks(old.Task("forecast-rain"))
+
+	updated := buildGraphflow()
+	updated.NewTaskGroup("Forecasting").AddTasks(updated.Task("forecast-sun"))
+	forecastFog := updated.AddTask(new(TaskWithNoName))
+	updated.AddPath(updated.Task("is-the-sky-cloudy"), NO, forecastFog)
+	updated.AddPath(forecastFog, ALWAYS, updated.Task("end"))
+	updated.RemoveTask(updated.Task("forecast-rain"))
+	updated.AddPath(updated.Task("is-the-sky-cloudy"), YES, updated.Task("forecast-sun"))
+
+	d := Diff(old, updated)
+
+	assert.False(t, d.Empty())
+	assert.Equal(t, []string{"unnamed-task"}, d.AddedTasks)
+	assert.Equal(t, []string{"forecast-rain"}, d.RemovedTasks)
+	assert.Equal(t, []PathChange{{From: "unnamed-task", Condition: ALWAYS, To: "end"}}, d.AddedPaths)
+	assert.Equal(t, []PathChange{{From: "forecast-rain", Condition: ALWAYS, To: "end"}}, d.RemovedPaths)
+	assert.Equal(t, []PathChange{
+		{From: "is-the-sky-cloudy", Condition: YES, To: "forecast-sun", PreviousTo: "forecast-rain"},
+		{From: "is-the-sky-cloudy", Condition: NO, To: "unnamed-task", PreviousTo: "forecast-sun"},
+	}, d.RetargetedPaths)
+	assert.Equal(t, []GroupChange{{TaskID: "forecast-sun", PreviousGroup: "", Group: "Forecasting"}}, d.GroupChanges)
+	assert.Equal(t, `+ task unnamed-task
+- task forecast-rain
++ path unnamed-task ALWAYS -> end
+- path forecast-rain ALWAYS -> end
+~ path is-the-sky-cloudy YES -> forecast-sun (was forecast-rain)
+~ path is-the-sky-cloudy NO -> unnamed-task (was forecast-sun)
+~ group forecast-sun "" -> "Forecasting"
+`, d.String())
+}
+
+func TestDiffOfNestedTaskGroups(t *testing.T) {
+	old := buildGraphflow()
+	old.NewTaskGroup("Rain").NewTaskGroup("Checks").AddTasks(old.Task("is-the-sky-cloudy"))
+	old.NewTaskGroup("Sun").NewTaskGroup("Checks")
+
+	updated := buildGraphflow()
+	updated.NewTaskGroup("Rain").NewTaskGroup("Checks")
+	updated.NewTaskGroup("Sun").NewTaskGroup("Checks").AddTasks(updated.Task("is-the-sky-cloudy"))
+
+	d := Diff(old, updated)
+
+	assert.NotEqual(t, old.Hash(), updated.Hash())
+	assert.Equal(t, []GroupChange{{TaskID: "is-the-sky-cloudy", PreviousGroup: "Rain / Checks", Group: "Sun / Checks"}}, d.GroupChanges)
+	assert.Equal(t, "~ group is-the-sky-cloudy \"Rain / Checks\" -> \"Sun / Checks\"\n", d.String())
+}
@@ -0,0 +1,63 @@
+package graphflow
+
+// ActionFunc returns a Task named name that calls fn when it's executed, for simple Tasks that don't
+// warrant a struct of their own. Its ExitPath is always ALWAYS.
+//
+// Example:
+//
+//	forecastRain := gf.AddTask(graphflow.ActionFunc("Forecast Rain", func(ctx *graphflow.ExecutionContext) error {
+//		ctx.Set("Forecast", "Rain")
+//		return nil
+//	}))
+func ActionFunc(name string, fn func(*ExecutionContext) error) TaskIntf {
+	return &actionTask{name: name, fn: fn}
+}
+
+// QuestionFunc returns a Task named name that calls fn when it's executed and sets its ExitPath to YES or NO
+// depending on the answer fn returns.
+//
+// Example:
+//
+//	isTheSkyCloudy := gf.AddTask(graphflow.QuestionFunc("Is the sky cloudy?", func(ctx *graphflow.ExecutionContext) (bool, error) {
+//		return ctx.Get("Sky") == "Cloudy", nil
+//	}))
+func QuestionFunc(name string, fn func(*ExecutionContext) (bool, error)) TaskIntf {
+	return &questionTask{name: name, fn: fn}
+}
+
+type actionTask struct {
+	Task
+	name string
+	fn   func(*ExecutionContext) error
+}
+
+func (t *actionTask) String() string {
+	return t.name
+}
+
+func (t *actionTask) Execute(ctx *ExecutionContext) error {
+	return t.fn(ctx)
+}
+
+type questionTask struct {
+	Task
+	name string
+	fn   func(*ExecutionContext) (bool, error)
+}
+
+func (t *questionTask) String() string {
+	return t.name
+}
+
+func (t *questionTask) Execute(ctx *ExecutionContext) error {
+	answer, err := t.fn(ctx)
+	if err != nil {
+		return err
+	}
+	if answer {
+		t.SetExitPath(YES)
+	} else {
+		t.SetExitPath(NO)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package graphflow
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func buildFuncGraphflow() *Graphflow {
+	gf := new(Graphflow)
+
+	// create task instances from functions
+	start := gf.AddTask(new(StartTask))
+	isTheSkyCloudy := gf.AddTask(QuestionFunc("Is the sky cloudy?", func(ctx *ExecutionContext) (bool, error) {
+		sky, ok := ctx.Get("Sky").(string)
+		if !ok {
+			return false, errors.New("Failed to retrieve \"Sky\" from ExecutionContext and cast it to string")
+		}
+		return sky == "Cloudy", nil
+	}))
+	forecastRain := gf.AddTask(ActionFunc("Forecast Rain", func(ctx *ExecutionContext) error {
+		ctx.Set("Forecast", "Rain")
+		return nil
+	}))
+	forecastSun := gf.AddTask(ActionFunc("Forecast Sun", func(ctx *ExecutionContext) error {
+		ctx.Set("Forecast", "Sun")
+		return nil
+	}))
+	end := gf.AddTask(new(EndTask))
+
+	// add task paths to the graphflow
+	gf.AddPath(start, ALWAYS, isTheSkyCloudy)
+	gf.AddPath(isTheSkyCloudy, YES, forecastRain)
+	gf.AddPath(isTheSkyCloudy, NO, forecastSun)
+	gf.AddPath(forecastRain, ALWAYS, end)
+	gf.AddPath(forecastSun, ALWAYS, end)
+
+	return gf
+}
+
+func TestFuncGraphflowCloudySky(t *testing.T) {
+	ctx := new(ExecutionContext)
+	ctx.Set("Sky", "Cloudy")
+
+	gf := buildFuncGraphflow()
+
+	err := gf.Run(ctx)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Rain", ctx.Get("Forecast"))
+	assert.Equal(t, YES, gf.Tasks()[1].ExitPath())
+	assert.Equal(t, "Is the sky cloudy?", gf.Tasks()[1].String())
+}
+
+func TestFuncGraphflowClearSky(t *testing.T) {
+	ctx := new(ExecutionContext)
+	ctx.Set("Sky", "Clear")
+
+	gf := buildFuncGraphflow()
+
+	err := gf.Run(ctx)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Sun", ctx.Get("Forecast"))
+	assert.Equal(t, NO, gf.Tasks()[1].ExitPath())
+	assert.Equal(t, "Forecast Sun", gf.Tasks()[3].String())
+}
+
+func TestQuestionFuncErrorIsReturned(t *testing.T) {
+	gf := buildFuncGraphflow()
+
+	err := gf.Run(new(ExecutionContext)) // no "Sky" set
+
+	assert.NotNil(t, err)
+}
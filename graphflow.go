@@ -10,6 +10,9 @@ package graphflow
 import (
 	"errors"
 	"fmt"
+	"sort"
+	"strings"
+	"time"
 )
 
 // PathCondition is a type representing the condition that should be satisfied for a certain path
@@ -24,7 +27,8 @@ const (
 	YES PathCondition = 1
 	// NO is the PathCondition that should be set by a question node if its condition fails
 	NO PathCondition = 2
-	// ERROR is the PathCondition that should be set by any node if you want a particular path to be followed in the event of an error
+	// ERROR is the PathCondition that should be set by any node if you want a particular path to be followed in the event of an error.
+	// It's also followed when a Task with an ERROR path returns an error from Execute.
 	ERROR PathCondition = 3
 )
 
@@ -36,14 +40,80 @@ var PathConditionName = map[PathCondition]string{
 	3: "ERROR",
 }
 
+// ParsePathCondition returns the PathCondition with the given textual name. An empty name is treated as ALWAYS.
+func ParsePathCondition(name string) (PathCondition, error) {
+	if name == "" {
+		return ALWAYS, nil
+	}
+	for condition, conditionName := range PathConditionName {
+		if conditionName == name {
+			return condition, nil
+		}
+	}
+	return ALWAYS, fmt.Errorf("\"%s\" is not a PathCondition", name)
+}
+
 // Graphflow represents a series of Tasks with defined Paths between them constructed as a simple workflow.
 // Graphflow methods support its construction, execution and rendering as a graphflow png.
 type Graphflow struct {
 	context    *ExecutionContext
 	tasks      []TaskIntf
+	ids        map[TaskIntf]string
 	taskGroups []*TaskGroup
 	paths      map[TaskIntf]map[PathCondition]TaskIntf
+	pathOrder  []pathKey
 	executed   map[TaskIntf]bool
+	result     *RunResult
+	version    string
+	// recordContext is set if each Step should record a snapshot of the ExecutionContext
+	recordContext bool
+	// metadata holds the Metadata set with SetMetadata
+	metadata map[TaskIntf]Metadata
+	// coverage collects the result of each run, if set with CollectCoverage
+	coverage *Coverage
+	// typeNames holds the type name each Task was built with, if the graphflow was built by a Registry
+	typeNames map[TaskIntf]string
+}
+
+// RunResult records the outcome of the most recent Run of a graphflow. It can be marshalled to JSON, so runs can be
+// stored and rendered later.
+type RunResult struct {
+	// Ended is true if the run reached an EndTask
+	Ended bool `json:"ended"`
+	// Outcome is the outcome name of the EndTask that was reached, empty for the default EndTask
+	Outcome string `json:"outcome,omitempty"`
+	// Steps is the trace of the Tasks executed, in the order they were executed
+	Steps []Step `json:"steps"`
+	// Version is the version of the graphflow that was run, as set by SetVersion
+	Version string `json:"version,omitempty"`
+	// Hash is the content hash of the graphflow that was run, as returned by Hash
+	Hash string `json:"hash"`
+	// Next is the ID of the Task that failed if the run stopped with an error. The run can be continued
+	// from that Task with Resume.
+	Next string `json:"next,omitempty"`
+	// Error is the message of the error returned by the Task that failed, if the run stopped with one
+	Error string `json:"error,omitempty"`
+}
+
+// Step records the execution of a single Task during a Run. It's marshalled to JSON with its ExitPath by name.
+type Step struct {
+	// TaskID is the ID of the Task that was executed
+	TaskID string
+	// ExitPath is the PathCondition the Task set when it was executed
+	ExitPath PathCondition
+	// Started is when the Task started executing
+	Started time.Time
+	// Duration is how long the Task took to execute
+	Duration time.Duration
+	// Context is a snapshot of the ExecutionContext after the Task was executed, if RecordContext was set
+	Context map[string]interface{}
+	// Error is the message of the error the Task returned, if it was handled by following an ERROR Path or a
+	// TaskGroup's error handler
+	Error string
+	// Owner is the owner of the Task, from its Metadata
+	Owner string
+	// OverSLA is true if the Task took longer than the SLA in its Metadata
+	OverSLA bool
 }
 
 // ExecutionContext is a map of values of any type that is passed from Task to Task as the graphflow is executed
@@ -52,6 +122,7 @@ type Graphflow struct {
 // for retrieval after execution has completed.
 type ExecutionContext struct {
 	values map[string]interface{}
+	err    error
 }
 
 // GetContext retrieves the current ExecutionContext from the graphflow
@@ -71,16 +142,98 @@ func (gf *Graphflow) Paths() map[TaskIntf]map[PathCondition]TaskIntf {
 	return gf.paths
 }
 
+// Path is a Path between two Tasks, followed when From exits with the PathCondition
+type Path struct {
+	From      TaskIntf
+	Condition PathCondition
+	To        TaskIntf
+}
+
+// pathKey identifies a Path by the Task it leaves and its PathCondition
+type pathKey struct {
+	from      TaskIntf
+	condition PathCondition
+}
+
+// OrderedPaths returns the Paths of the graphflow in the order they were added, so anything generated from them,
+// like a rendered graph, is the same every time. Retargeting a Path with AddPath keeps its place in the order.
+func (gf *Graphflow) OrderedPaths() []Path {
+	paths := []Path{}
+	seen := make(map[pathKey]bool)
+	for _, key := range gf.pathOrder {
+		if to, exists := gf.paths[key.from][key.condition]; exists && !seen[key] {
+			paths = append(paths, Path{From: key.from, Condition: key.condition, To: to})
+			seen[key] = true
+		}
+	}
+	// Paths set directly on the map returned by Paths come last, in Task and PathCondition order
+	for _, from := range gf.tasks {
+		conditions := []PathCondition{}
+		for condition := range gf.paths[from] {
+			if !seen[pathKey{from: from, condition: condition}] {
+				conditions = append(conditions, condition)
+			}
+		}
+		sort.Slice(conditions, func(i, j int) bool { return conditions[i] < conditions[j] })
+		for _, condition := range conditions {
+			paths = append(paths, Path{From: from, Condition: condition, To: gf.paths[from][condition]})
+		}
+	}
+	return paths
+}
+
 func (gf *Graphflow) Executed() map[TaskIntf]bool {
 	return gf.executed
 }
 
-// AddTask adds a new Task (a struct implementing the TaskIntf interface) to the graphflow
+// RecordContext sets whether the Steps of a RunResult record a snapshot of the ExecutionContext after each Task,
+// so a run can be replayed step by step. It's off by default, as copying the ExecutionContext isn't free.
+func (gf *Graphflow) RecordContext(record bool) {
+	gf.recordContext = record
+}
+
+// Result returns the RunResult of the most recent Run, or nil if the graphflow hasn't been run
+func (gf *Graphflow) Result() *RunResult {
+	return gf.result
+}
+
+// AddTask adds a new Task (a struct implementing the TaskIntf interface) to the graphflow. The Task is given an ID
+// generated from its name, eg "is-the-sky-cloudy", with a numeric suffix if that ID is already in use.
 func (gf *Graphflow) AddTask(task TaskIntf) TaskIntf {
+	base := slug(task.String())
+	id := base
+	for i := 2; gf.Task(id) != nil; i++ {
+		id = fmt.Sprintf("%s-%d", base, i)
+	}
+	return gf.AddTaskWithID(id, task)
+}
+
+// AddTaskWithID adds a new Task to the graphflow with the given ID. IDs give each Task a stable handle for
+// definitions, logs and traces, so they need to be unique within the graphflow.
+func (gf *Graphflow) AddTaskWithID(id string, task TaskIntf) TaskIntf {
+	if gf.ids == nil {
+		gf.ids = make(map[TaskIntf]string)
+	}
 	gf.tasks = append(gf.tasks, task)
+	gf.ids[task] = id
 	return task
 }
 
+// Task returns the Task with the given ID, or nil if there isn't one
+func (gf *Graphflow) Task(id string) TaskIntf {
+	for _, task := range gf.tasks {
+		if gf.ids[task] == id {
+			return task
+		}
+	}
+	return nil
+}
+
+// TaskID returns the ID of a Task in the graphflow, or an empty string if it hasn't been added
+func (gf *Graphflow) TaskID(task TaskIntf) string {
+	return gf.ids[task]
+}
+
 // AddPath adds conditional Paths between graphflow Tasks
 func (gf *Graphflow) AddPath(from TaskIntf, condition PathCondition, to TaskIntf) {
 	if gf.paths == nil {
@@ -89,15 +242,44 @@ func (gf *Graphflow) AddPath(from TaskIntf, condition PathCondition, to TaskIntf
 	if gf.paths[from] == nil {
 		gf.paths[from] = make(map[PathCondition]TaskIntf)
 	}
+	if _, exists := gf.paths[from][condition]; !exists {
+		gf.pathOrder = append(gf.pathOrder, pathKey{from: from, condition: condition})
+	}
 	gf.paths[from][condition] = to
 }
 
 // Run passes the graphflow an ExecutionContext and, starting at the StartTask, follows conditional Paths
-// through the graphflow, executing each Task until it reaches the EndTask.
+// through the graphflow, executing each Task until it reaches the EndTask. Each Task is executed at most once, so
+// Paths back to a Task that has already run aren't followed.
 func (gf *Graphflow) Run(context *ExecutionContext) error {
+	return gf.RunFrom("", context)
+}
+
+// RunFrom behaves like Run but starts at the StartTask with the given entry point name, allowing a single
+// graphflow to be entered at several places (eg "onboarding" or "renewal"). An empty name selects the
+// default, unnamed StartTask.
+func (gf *Graphflow) RunFrom(entryName string, context *ExecutionContext) error {
 	gf.context = context
 	gf.executed = make(map[TaskIntf]bool)
-	err := gf.execute()
+	gf.result = &RunResult{
+		Version: gf.version,
+		Hash:    gf.Hash(),
+	}
+	err := gf.validateTasks()
+	if err != nil {
+		return err
+	}
+	t, err := gf.findStartTask(entryName)
+	if err != nil {
+		return err
+	}
+	err = gf.execute(t)
+	if gf.coverage != nil {
+		// the run's own error comes first, but a run that couldn't be added to the coverage shouldn't pass quietly
+		if coverageErr := gf.coverage.Add(gf.result); coverageErr != nil && err == nil {
+			return coverageErr
+		}
+	}
 	if err != nil {
 		return err
 	}
@@ -110,6 +292,25 @@ func (ctx *ExecutionContext) Get(v string) interface{} {
 	return ctx.values[v]
 }
 
+// Snapshot returns a copy of the values in the ExecutionContext. Values are copied shallowly, so maps, slices and
+// pointers are shared with the ExecutionContext.
+func (ctx *ExecutionContext) Snapshot() map[string]interface{} {
+	if ctx == nil {
+		return map[string]interface{}{}
+	}
+	snapshot := make(map[string]interface{}, len(ctx.values))
+	for k, v := range ctx.values {
+		snapshot[k] = v
+	}
+	return snapshot
+}
+
+// Err returns the most recent error returned by a Task that was handled by following its ERROR Path or its
+// TaskGroup's error handler, so the Task handling it can report it
+func (ctx *ExecutionContext) Err() error {
+	return ctx.err
+}
+
 // Set sets a specific value in the ExecutionContext, updating it if if already exists
 func (ctx *ExecutionContext) Set(key string, value interface{}) {
 	if ctx.values == nil {
@@ -136,6 +337,17 @@ type TaskIntf interface {
 	ExitPath() PathCondition
 }
 
+// Describer can be implemented by Tasks to explain what they do, in more detail than the name returned by String
+type Describer interface {
+	Description() string
+}
+
+// IODeclarer can be implemented by Tasks to declare the ExecutionContext keys they read and write
+type IODeclarer interface {
+	Inputs() []string
+	Outputs() []string
+}
+
 // Task is a struct that all new Tasks should include in their definition.
 //
 // Example:
@@ -171,30 +383,84 @@ func (t *Task) String() string {
 }
 
 // StartTask is a Task provided by the package. Every graphflow must include a StartTask.
+// A graphflow may contain further StartTasks created with NewStartTask, each naming an
+// entry point that can be run with RunFrom.
 type StartTask struct {
 	Task
+	name string
+}
+
+// NewStartTask creates a StartTask for the named entry point. Passing an empty name is the same as new(StartTask).
+func NewStartTask(name string) *StartTask {
+	return &StartTask{name: name}
+}
+
+// Name returns the entry point name of the StartTask, or an empty string for the default StartTask
+func (t *StartTask) Name() string {
+	return t.name
+}
+
+// Params returns the entry point name of a named StartTask, for use in workflow definitions
+func (t *StartTask) Params() map[string]interface{} {
+	if t.name == "" {
+		return nil
+	}
+	return map[string]interface{}{"entry": t.name}
 }
 
 // String returns the name of the StartTask
 func (t *StartTask) String() string {
+	if t.name != "" {
+		return fmt.Sprintf("Start (%s)", t.name)
+	}
 	return "Start"
 }
 
 // EndTask is a Task provided by the package. Every graphflow must include an EndTask.
+// A graphflow with several terminal outcomes (eg "Approved", "Rejected", "Referred") can include one
+// EndTask per outcome, created with NewEndTask.
 type EndTask struct {
 	Task
+	outcome string
+}
+
+// NewEndTask creates an EndTask for the named outcome. Passing an empty outcome is the same as new(EndTask).
+func NewEndTask(outcome string) *EndTask {
+	return &EndTask{outcome: outcome}
+}
+
+// Outcome returns the outcome name of the EndTask, or an empty string for the default EndTask
+func (t *EndTask) Outcome() string {
+	return t.outcome
+}
+
+// Params returns the outcome name of a named EndTask, for use in workflow definitions
+func (t *EndTask) Params() map[string]interface{} {
+	if t.outcome == "" {
+		return nil
+	}
+	return map[string]interface{}{"outcome": t.outcome}
 }
 
 // String returns the name of the EndTask
 func (t *EndTask) String() string {
+	if t.outcome != "" {
+		return fmt.Sprintf("End (%s)", t.outcome)
+	}
 	return "End"
 }
 
 // TaskGroup can have Tasks added to it, meaning they'll be rendered together with a box around them and a label set to the
-// TaskGroup's name
+// TaskGroup's name. TaskGroups can be nested by creating them with a TaskGroup's NewTaskGroup method.
 type TaskGroup struct {
-	name  string
-	tasks []TaskIntf
+	name         string
+	tasks        []TaskIntf
+	parent       *TaskGroup
+	graphflow    *Graphflow
+	color        string
+	description  string
+	errorHandler TaskIntf
+	policy       Policy
 }
 
 // AddTasks allows Tasks to be added to a TaskGroup
@@ -210,11 +476,65 @@ func (t *TaskGroup) Name() string {
 	return t.name
 }
 
+// NewTaskGroup creates a TaskGroup nested inside this one, which is rendered as a box inside this TaskGroup's box
+func (t *TaskGroup) NewTaskGroup(name string) *TaskGroup {
+	taskGroup := t.graphflow.NewTaskGroup(name)
+	taskGroup.parent = t
+	return taskGroup
+}
+
+// Parent returns the TaskGroup this one is nested inside, or nil if it isn't nested
+func (t *TaskGroup) Parent() *TaskGroup {
+	return t.parent
+}
+
+// QualifiedName returns the name of the TaskGroup prefixed by the names of the TaskGroups it's nested inside, eg
+// "Forecasting / Rain", which tells apart TaskGroups with the same name in different places
+func (t *TaskGroup) QualifiedName() string {
+	name := t.name
+	for parent := t.parent; parent != nil; parent = parent.parent {
+		name = fmt.Sprintf("%s / %s", parent.name, name)
+	}
+	return name
+}
+
+// TaskGroups returns the TaskGroups nested directly inside this one
+func (t *TaskGroup) TaskGroups() []*TaskGroup {
+	taskGroups := []*TaskGroup{}
+	for _, taskGroup := range t.graphflow.taskGroups {
+		if taskGroup.parent == t {
+			taskGroups = append(taskGroups, taskGroup)
+		}
+	}
+	return taskGroups
+}
+
+// SetColor sets the background colour the TaskGroup is rendered with, as a Graphviz colour name or hex value
+func (t *TaskGroup) SetColor(color string) {
+	t.color = color
+}
+
+// Color returns the background colour set with SetColor, or an empty string if the theme's colour is used
+func (t *TaskGroup) Color() string {
+	return t.color
+}
+
+// SetDescription sets a description of the TaskGroup, which is shown as its tooltip when rendered
+func (t *TaskGroup) SetDescription(description string) {
+	t.description = description
+}
+
+// Description returns the description set with SetDescription
+func (t *TaskGroup) Description() string {
+	return t.description
+}
+
 // NewTaskGroup creates a new TaskGroup with the provided name and adds it to the graphflow. Add Tasks to the *TaskGroup it returns
 // for them to be rendered together with a box around them and a label set to the TaskGroup's name
 func (gf *Graphflow) NewTaskGroup(name string) *TaskGroup {
 	taskGroup := &TaskGroup{
-		name: name,
+		name:      name,
+		graphflow: gf,
 	}
 	gf.taskGroups = append(gf.taskGroups, taskGroup)
 	return taskGroup
@@ -249,15 +569,7 @@ func (q *taskQueue) isEmpty() bool {
 	return len(q.tasks) == 0
 }
 
-func (gf *Graphflow) execute() error {
-	err := gf.validateTasks()
-	if err != nil {
-		return err
-	}
-	t, err := gf.findStartTask()
-	if err != nil {
-		return err
-	}
+func (gf *Graphflow) execute(t TaskIntf) error {
 	q := taskQueue{}
 	q.new()
 	q.enqueue(t)
@@ -268,9 +580,49 @@ func (gf *Graphflow) execute() error {
 		}
 		task := q.dequeue()
 		visited[task] = true
-		err := task.Execute(gf.context)
+		started := time.Now()
+		err := gf.executeTask(task)
+		var handler TaskIntf
 		if err != nil {
-			return err
+			handler = gf.errorHandler(task)
+			if handler == nil {
+				gf.result.Next = gf.ids[task]
+				gf.result.Error = err.Error()
+				return err
+			}
+			task.SetExitPath(ERROR)
+			if gf.context != nil {
+				gf.context.err = err
+			}
+		}
+		step := Step{
+			TaskID:   gf.ids[task],
+			ExitPath: task.ExitPath(),
+			Started:  started,
+			Duration: time.Since(started),
+		}
+		if err != nil {
+			step.Error = err.Error()
+		}
+		if metadata := gf.Metadata(task); !metadata.IsZero() {
+			step.Owner = metadata.Owner
+			step.OverSLA = metadata.SLA > 0 && step.Duration > metadata.SLA
+		}
+		if gf.recordContext && gf.context != nil {
+			step.Context = gf.context.Snapshot()
+		}
+		gf.result.Steps = append(gf.result.Steps, step)
+		if endTask, isEndTask := task.(*EndTask); isEndTask {
+			gf.result.Ended = true
+			gf.result.Outcome = endTask.outcome
+		}
+
+		if handler != nil {
+			if !visited[handler] {
+				q.enqueue(handler)
+				visited[handler] = true
+			}
+			continue
 		}
 
 		near := gf.paths[task]
@@ -289,13 +641,16 @@ func (gf *Graphflow) execute() error {
 	return nil
 }
 
-func (gf *Graphflow) findStartTask() (TaskIntf, error) {
+func (gf *Graphflow) findStartTask(entryName string) (TaskIntf, error) {
 	for _, task := range gf.tasks {
-		_, isStartTask := task.(*StartTask)
-		if isStartTask {
+		startTask, isStartTask := task.(*StartTask)
+		if isStartTask && startTask.name == entryName {
 			return task, nil
 		}
 	}
+	if entryName != "" {
+		return nil, fmt.Errorf("Workflow has no StartTask for the entry point \"%s\"", entryName)
+	}
 	return nil, errors.New("Workflow needs to contain a task of type StartTask")
 }
 
@@ -314,7 +669,50 @@ func (gf *Graphflow) validateTasks() error {
 	if err != nil {
 		return err
 	}
+	ids := make(map[string]bool)
+	for _, task := range gf.tasks {
+		id := gf.ids[task]
+		if id == "" {
+			return fmt.Errorf("Task %s needs a non-empty ID", task)
+		}
+		if ids[id] {
+			return fmt.Errorf("Task ID \"%s\" is used by more than one Task", id)
+		}
+		ids[id] = true
+	}
+	entryPoints := make(map[string]bool)
+	outcomes := make(map[string]bool)
+	for _, task := range gf.tasks {
+		switch t := task.(type) {
+		case *StartTask:
+			if entryPoints[t.name] {
+				if t.name == "" {
+					return errors.New("Workflow can only contain one default StartTask, use NewStartTask to add named entry points")
+				}
+				return fmt.Errorf("Workflow contains more than one StartTask for the entry point \"%s\"", t.name)
+			}
+			entryPoints[t.name] = true
+		case *EndTask:
+			if outcomes[t.outcome] {
+				if t.outcome == "" {
+					return errors.New("Workflow can only contain one default EndTask, use NewEndTask to add named outcomes")
+				}
+				return fmt.Errorf("Workflow contains more than one EndTask for the outcome \"%s\"", t.outcome)
+			}
+			outcomes[t.outcome] = true
+		}
+	}
+	for _, to := range gf.paths {
+		for _, task := range to {
+			if gf.isDeadEnd(task) {
+				return fmt.Errorf("Task %s is a dead end, every path needs to finish at an EndTask", task.String())
+			}
+		}
+	}
 	for task := range gf.paths {
+		if _, isEndTask := task.(*EndTask); isEndTask && len(gf.paths[task]) > 0 {
+			return fmt.Errorf("EndTask %s cannot have paths leaving it", task.String())
+		}
 		conditions := []PathCondition{}
 		for condition := range gf.paths[task] {
 			conditions = append(conditions, condition)
@@ -336,6 +734,17 @@ func (gf *Graphflow) validateTasks() error {
 		}
 	}
 	for _, taskGroup := range gf.taskGroups {
+		if policy := taskGroup.policy; policy.Retries < 0 || policy.RetryDelay < 0 || policy.Timeout < 0 {
+			return fmt.Errorf("TaskGroup \"%s\" has a Policy with a negative number of retries, retry delay or timeout", taskGroup.name)
+		}
+		if handler := taskGroup.errorHandler; handler != nil {
+			if !gf.hasTask(handler) {
+				return fmt.Errorf("TaskGroup \"%s\" has an error handler %s which hasn't been added to the graphflow", taskGroup.name, handler)
+			}
+			if gf.isDeadEnd(handler) {
+				return fmt.Errorf("Task %s is a dead end, every path needs to finish at an EndTask", handler.String())
+			}
+		}
 		for _, t := range taskGroup.tasks {
 			for _, otherTaskGroup := range gf.taskGroups {
 				if taskGroup == otherTaskGroup {
@@ -349,9 +758,98 @@ func (gf *Graphflow) validateTasks() error {
 			}
 		}
 	}
+	return gf.validateEndReachable()
+}
+
+// validateEndReachable returns an error if a Task that can be reached from a StartTask can't reach an EndTask, eg
+// because it's in a cycle that never leaves. ERROR Paths and the error handlers of TaskGroups are followed too.
+func (gf *Graphflow) validateEndReachable() error {
+	next := func(task TaskIntf) []TaskIntf {
+		tasks := []TaskIntf{}
+		for _, to := range gf.paths[task] {
+			tasks = append(tasks, to)
+		}
+		if handler := gf.errorHandler(task); handler != nil {
+			tasks = append(tasks, handler)
+		}
+		return tasks
+	}
+	reachable := make(map[TaskIntf]bool)
+	var reach func(task TaskIntf)
+	reach = func(task TaskIntf) {
+		if reachable[task] {
+			return
+		}
+		reachable[task] = true
+		for _, to := range next(task) {
+			reach(to)
+		}
+	}
+	for _, task := range gf.tasks {
+		if _, isStartTask := task.(*StartTask); isStartTask {
+			reach(task)
+		}
+	}
+	// Tasks that can reach an EndTask are found by working back from the EndTasks until nothing changes
+	ends := make(map[TaskIntf]bool)
+	for changed := true; changed; {
+		changed = false
+		for task := range reachable {
+			if ends[task] {
+				continue
+			}
+			_, isEndTask := task.(*EndTask)
+			for _, to := range next(task) {
+				isEndTask = isEndTask || ends[to]
+			}
+			if isEndTask {
+				ends[task] = true
+				changed = true
+			}
+		}
+	}
+	for _, task := range gf.tasks {
+		// Tasks without Paths are either dead ends, which are reported above, or a StartTask on its own
+		if reachable[task] && !ends[task] && len(gf.paths[task]) > 0 {
+			return fmt.Errorf("Task %s can't reach an EndTask, every path needs to finish at an EndTask", task.String())
+		}
+	}
 	return nil
 }
 
+// slug turns a Task's name into an ID made up of lower case letters, digits and dashes
+func slug(name string) string {
+	var sb strings.Builder
+	dash := false
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			if dash && sb.Len() > 0 {
+				sb.WriteRune('-')
+			}
+			sb.WriteRune(r)
+			dash = false
+		} else {
+			dash = true
+		}
+	}
+	if sb.Len() == 0 {
+		return "task"
+	}
+	return sb.String()
+}
+
+// isDeadEnd returns true if a Task isn't an EndTask and has no Path to follow when it succeeds, as an ERROR Path is
+// only followed when it fails
+func (gf *Graphflow) isDeadEnd(task TaskIntf) bool {
+	if _, isEndTask := task.(*EndTask); isEndTask {
+		return false
+	}
+	_, hasAlways := gf.paths[task][ALWAYS]
+	_, hasYes := gf.paths[task][YES]
+	_, hasNo := gf.paths[task][NO]
+	return !hasAlways && !hasYes && !hasNo
+}
+
 func contains(conditions []PathCondition, condition PathCondition) bool {
 	for _, c := range conditions {
 		if c == condition {
@@ -262,3 +262,336 @@ func TestWorkflowWithNoPathsShouldRunFine(t *testing.T) {
 
 	assert.Nil(t, err)
 }
+
+func TestGraphWithTwoStartTasksThrowsError(t *testing.T) {
+	gf := buildGraphflow()
+
+	gf.AddTask(new(StartTask)) // this should cause Run() to error
+
+	err := gf.Run(new(ExecutionContext))
+
+	assert.NotNil(t, err)
+}
+
+func TestGraphWithTwoEndTasksThrowsError(t *testing.T) {
+	gf := buildGraphflow()
+
+	gf.AddTask(new(EndTask)) // this should cause Run() to error
+
+	err := gf.Run(new(ExecutionContext))
+
+	assert.NotNil(t, err)
+}
+
+func TestGraphWithDuplicateEntryPointsThrowsError(t *testing.T) {
+	gf := buildGraphflow()
+
+	gf.AddTask(NewStartTask("renewal"))
+	gf.AddTask(NewStartTask("renewal")) // this should cause RunFrom() to error
+
+	err := gf.RunFrom("renewal", new(ExecutionContext))
+
+	assert.NotNil(t, err)
+}
+
+func TestRunFromNamedEntryPoint(t *testing.T) {
+	ctx := new(ExecutionContext)
+	ctx.Set("Sky", "Cloudy")
+	ctx.Set("Forecast", "")
+
+	gf := buildGraphflow()
+
+	// enter the graphflow part way through, skipping the question
+	renewal := gf.AddTask(NewStartTask("renewal"))
+	gf.AddPath(renewal, ALWAYS, gf.Tasks()[3]) // Forecast Sun
+
+	err := gf.RunFrom("renewal", ctx)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Sun", ctx.Get("Forecast"))
+	assert.Equal(t, "Start (renewal)", renewal.String())
+
+	err = gf.Run(ctx)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Rain", ctx.Get("Forecast"))
+}
+
+func TestRunFromUnknownEntryPointThrowsError(t *testing.T) {
+	gf := buildGraphflow()
+
+	err := gf.RunFrom("onboarding", new(ExecutionContext))
+
+	assert.NotNil(t, err)
+}
+
+func buildOutcomeGraphflow() *Graphflow {
+	gf := new(Graphflow)
+
+	// create task instances
+	start := gf.AddTask(new(StartTask))
+	isTheSkyCloudy := gf.AddTask(new(IsTheSkyCloudy))
+	takeUmbrella := gf.AddTask(NewEndTask("Take Umbrella"))
+	wearSunglasses := gf.AddTask(NewEndTask("Wear Sunglasses"))
+
+	// add task paths to the graphflow
+	gf.AddPath(start, ALWAYS, isTheSkyCloudy)
+	gf.AddPath(isTheSkyCloudy, YES, takeUmbrella)
+	gf.AddPath(isTheSkyCloudy, NO, wearSunglasses)
+
+	return gf
+}
+
+func TestRunReportsOutcome(t *testing.T) {
+	ctx := new(ExecutionContext)
+	ctx.Set("Sky", "Cloudy")
+
+	gf := buildOutcomeGraphflow()
+
+	err := gf.Run(ctx)
+
+	assert.Nil(t, err)
+	assert.True(t, gf.Result().Ended)
+	assert.Equal(t, "Take Umbrella", gf.Result().Outcome)
+
+	ctx.Set("Sky", "Clear")
+
+	err = gf.Run(ctx)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Wear Sunglasses", gf.Result().Outcome)
+}
+
+func TestRunReportsDefaultOutcome(t *testing.T) {
+	ctx := new(ExecutionContext)
+	ctx.Set("Sky", "Cloudy")
+
+	gf := buildGraphflow()
+
+	err := gf.Run(ctx)
+
+	assert.Nil(t, err)
+	assert.True(t, gf.Result().Ended)
+	assert.Equal(t, "", gf.Result().Outcome)
+}
+
+func TestGraphWithDuplicateOutcomesThrowsError(t *testing.T) {
+	gf := buildOutcomeGraphflow()
+
+	gf.AddTask(NewEndTask("Take Umbrella")) // this should cause Run() to error
+
+	err := gf.Run(new(ExecutionContext))
+
+	assert.NotNil(t, err)
+}
+
+func TestPathWithDeadEndThrowsError(t *testing.T) {
+	var gf Graphflow
+
+	// create task instances
+	start := new(StartTask)
+	forecastSun := new(ForecastSun)
+	end := new(EndTask)
+
+	// add task instances to the graphflow
+	gf.AddTask(start)
+	gf.AddTask(forecastSun)
+	gf.AddTask(end)
+
+	// add task paths to the graphflow
+	gf.AddPath(start, ALWAYS, forecastSun) // forecastSun doesn't lead to an EndTask
+
+	err := gf.Run(new(ExecutionContext))
+
+	assert.NotNil(t, err)
+}
+
+func TestPathWithOnlyAnErrorPathThrowsError(t *testing.T) {
+	var gf Graphflow
+
+	// create task instances
+	start := new(StartTask)
+	forecastSun := new(ForecastSun)
+	end := new(EndTask)
+
+	// add task instances to the graphflow
+	gf.AddTask(start)
+	gf.AddTask(forecastSun)
+	gf.AddTask(end)
+
+	// add task paths to the graphflow
+	gf.AddPath(start, ALWAYS, forecastSun)
+	gf.AddPath(forecastSun, ERROR, end) // forecastSun only leads to an EndTask when it fails
+
+	err := gf.Run(new(ExecutionContext))
+
+	assert.EqualError(t, err, "Task Forecast Sun is a dead end, every path needs to finish at an EndTask")
+}
+
+func TestCycleWithoutEndTaskThrowsError(t *testing.T) {
+	var gf Graphflow
+
+	// create task instances
+	start := new(StartTask)
+	forecastRain := new(ForecastRain)
+	forecastSun := new(ForecastSun)
+	end := new(EndTask)
+
+	// add task instances to the graphflow
+	gf.AddTask(start)
+	gf.AddTask(forecastRain)
+	gf.AddTask(forecastSun)
+	gf.AddTask(end)
+
+	// add task paths to the graphflow
+	gf.AddPath(start, ALWAYS, forecastRain)
+	gf.AddPath(forecastRain, ALWAYS, forecastSun)
+	gf.AddPath(forecastSun, ALWAYS, forecastRain) // the cycle never leads to the EndTask
+
+	err := gf.Run(new(ExecutionContext))
+
+	assert.EqualError(t, err, "Task Start can't reach an EndTask, every path needs to finish at an EndTask")
+	assert.Empty(t, gf.AllPaths())
+}
+
+func TestPathLeavingEndTaskThrowsError(t *testing.T) {
+	gf := buildGraphflow()
+
+	gf.AddPath(gf.Tasks()[4], ALWAYS, gf.Tasks()[1]) // this should cause Run() to error
+
+	err := gf.Run(new(ExecutionContext))
+
+	assert.NotNil(t, err)
+}
+
+func TestTaskIDs(t *testing.T) {
+	gf := buildGraphflow()
+
+	// a second task with the same name gets a suffixed ID
+	forecastSun := gf.AddTask(new(ForecastSun))
+	forecastFog := gf.AddTaskWithID("fog", new(TaskWithNoName))
+
+	assert.Equal(t, "start", gf.TaskID(gf.Tasks()[0]))
+	assert.Equal(t, "is-the-sky-cloudy", gf.TaskID(gf.Tasks()[1]))
+	assert.Equal(t, "forecast-sun-2", gf.TaskID(forecastSun))
+	assert.Equal(t, "fog", gf.TaskID(forecastFog))
+	assert.Same(t, forecastFog, gf.Task("fog"))
+	assert.Nil(t, gf.Task("forecast-fog"))
+	assert.Equal(t, "", gf.TaskID(new(ForecastRain)))
+}
+
+func TestDuplicateTaskIDsThrowsError(t *testing.T) {
+	gf := buildGraphflow()
+
+	gf.AddTaskWithID("forecast-rain", new(TaskWithNoName)) // this should cause Run() to error
+
+	err := gf.Run(new(ExecutionContext))
+
+	assert.NotNil(t, err)
+}
+
+func TestRunResultSteps(t *testing.T) {
+	ctx := new(ExecutionContext)
+	ctx.Set("Sky", "Clear")
+
+	gf := buildGraphflow()
+
+	err := gf.Run(ctx)
+
+	assert.Nil(t, err)
+	steps := []Step{}
+	for _, step := range gf.Result().Steps {
+		assert.False(t, step.Started.IsZero())
+		assert.True(t, step.Duration >= 0)
+		steps = append(steps, Step{TaskID: step.TaskID, ExitPath: step.ExitPath})
+	}
+	assert.Equal(t, []Step{
+		{TaskID: "start", ExitPath: ALWAYS},
+		{TaskID: "is-the-sky-cloudy", ExitPath: NO},
+		{TaskID: "forecast-sun", ExitPath: ALWAYS},
+		{TaskID: "end", ExitPath: ALWAYS},
+	}, steps)
+}
+
+func TestOrderedPaths(t *testing.T) {
+	gf := new(Graphflow)
+	start := gf.AddTask(new(StartTask))
+	isTheSkyCloudy := gf.AddTask(new(IsTheSkyCloudy))
+	forecastRain := gf.AddTask(new(ForecastRain))
+	forecastSun := gf.AddTask(new(ForecastSun))
+	end := gf.AddTask(new(EndTask))
+	gf.AddPath(forecastSun, ALWAYS, end)
+	gf.AddPath(isTheSkyCloudy, NO, forecastSun)
+	gf.AddPath(isTheSkyCloudy, YES, forecastRain)
+	gf.AddPath(start, ALWAYS, isTheSkyCloudy)
+	gf.AddPath(forecastRain, ALWAYS, forecastSun)
+
+	// retargeting a Path keeps its place
+	gf.AddPath(forecastRain, ALWAYS, end)
+
+	assert.Equal(t, []Path{
+		{From: forecastSun, Condition: ALWAYS, To: end},
+		{From: isTheSkyCloudy, Condition: NO, To: forecastSun},
+		{From: isTheSkyCloudy, Condition: YES, To: forecastRain},
+		{From: start, Condition: ALWAYS, To: isTheSkyCloudy},
+		{From: forecastRain, Condition: ALWAYS, To: end},
+	}, gf.OrderedPaths())
+
+	// a removed Path goes to the end when it's added again
+	assert.Nil(t, gf.RemovePath(isTheSkyCloudy, NO))
+	gf.AddPath(isTheSkyCloudy, NO, forecastSun)
+	paths := gf.OrderedPaths()
+	assert.Equal(t, Path{From: isTheSkyCloudy, Condition: NO, To: forecastSun}, paths[len(paths)-1])
+
+	// clones keep the order
+	clone := gf.Clone()
+	clonePaths := clone.OrderedPaths()
+	for i, p := range paths {
+		assert.Equal(t, gf.TaskID(p.From), clone.TaskID(clonePaths[i].From))
+		assert.Equal(t, p.Condition, clonePaths[i].Condition)
+	}
+}
+
+func TestRecordContext(t *testing.T) {
+	ctx := new(ExecutionContext)
+	ctx.Set("Sky", "Cloudy")
+	gf := buildGraphflow()
+
+	assert.Nil(t, gf.Run(ctx))
+	assert.Nil(t, gf.Result().Steps[0].Context)
+
+	gf.RecordContext(true)
+	ctx.Set("Forecast", "")
+	assert.Nil(t, gf.Run(ctx))
+
+	steps := gf.Result().Steps
+	assert.Equal(t, map[string]interface{}{"Sky": "Cloudy", "Forecast": ""}, steps[0].Context)
+	assert.Equal(t, map[string]interface{}{"Sky": "Cloudy", "Forecast": "Rain"}, steps[2].Context)
+}
+
+func TestNestedTaskGroups(t *testing.T) {
+	gf := buildGraphflow()
+	forecasting := gf.NewTaskGroup("Forecasting")
+	forecasting.AddTasks(gf.Task("is-the-sky-cloudy"))
+	rain := forecasting.NewTaskGroup("Rain")
+	rain.AddTasks(gf.Task("forecast-rain"))
+	sun := forecasting.NewTaskGroup("Sun")
+	sun.AddTasks(gf.Task("forecast-sun"))
+
+	assert.Equal(t, []*TaskGroup{forecasting, rain, sun}, gf.TaskGroups())
+	assert.Nil(t, forecasting.Parent())
+	assert.Same(t, forecasting, rain.Parent())
+	assert.Equal(t, []*TaskGroup{rain, sun}, forecasting.TaskGroups())
+	assert.Empty(t, rain.TaskGroups())
+
+	ctx := new(ExecutionContext)
+	ctx.Set("Sky", "Cloudy")
+	assert.Nil(t, gf.Run(ctx))
+
+	// a Task can't be in a TaskGroup and a TaskGroup nested inside it
+	forecasting.AddTasks(gf.Task("forecast-rain"))
+
+	err := gf.Run(ctx)
+
+	assert.EqualError(t, err, "A Task can only exist in one TaskGroup but \"Forecast Rain\" exists in \"Forecasting\" and \"Rain\"")
+}
@@ -0,0 +1,67 @@
+package graphflow
+
+import (
+	"strings"
+	"time"
+)
+
+// Metadata documents a Task for the people who look after it. None of it affects how the Task is executed.
+type Metadata struct {
+	// Description explains what the Task does, in more detail than its name
+	Description string
+	// Owner is the team or person responsible for the Task
+	Owner string
+	// Tags label the Task so it can be picked out, eg when rendering
+	Tags []string
+	// DocURL links to the Task's documentation
+	DocURL string
+	// SLA is how long the Task is expected to take at most. Steps that take longer are marked OverSLA.
+	SLA time.Duration
+}
+
+// MetadataProvider can be implemented by Tasks to document themselves. Tasks that only implement Describer have
+// Metadata with just a Description.
+type MetadataProvider interface {
+	Metadata() Metadata
+}
+
+// IsZero returns true if none of the Metadata is set
+func (m Metadata) IsZero() bool {
+	return m.Description == "" && m.Owner == "" && len(m.Tags) == 0 && m.DocURL == "" && m.SLA == 0
+}
+
+// HasTag returns true if the Metadata has any of the given tags
+func (m Metadata) HasTag(tags ...string) bool {
+	for _, tag := range m.Tags {
+		for _, t := range tags {
+			if strings.EqualFold(tag, t) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// SetMetadata sets the Metadata of a Task in the graphflow, in place of any it provides itself. This lets Tasks
+// that don't implement MetadataProvider, such as ActionFuncs, be documented.
+func (gf *Graphflow) SetMetadata(task TaskIntf, metadata Metadata) {
+	if gf.metadata == nil {
+		gf.metadata = make(map[TaskIntf]Metadata)
+	}
+	gf.metadata[task] = metadata
+}
+
+// Metadata returns the Metadata of a Task: the Metadata set with SetMetadata if there is any, or else the Metadata
+// the Task provides by implementing MetadataProvider or Describer
+func (gf *Graphflow) Metadata(task TaskIntf) Metadata {
+	if metadata, exists := gf.metadata[task]; exists {
+		return metadata
+	}
+	if provider, ok := task.(MetadataProvider); ok {
+		return provider.Metadata()
+	}
+	if describer, ok := task.(Describer); ok {
+		return Metadata{Description: describer.Description()}
+	}
+	return Metadata{}
+}
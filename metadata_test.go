@@ -0,0 +1,99 @@
+package graphflow
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// DocumentedForecast is a Task struct that provides Metadata
+type DocumentedForecast struct {
+	Task
+}
+
+// String returns a description of the Task
+func (t *DocumentedForecast) String() string {
+	return "Documented Forecast"
+}
+
+// Metadata documents the Task
+func (t *DocumentedForecast) Metadata() Metadata {
+	return Metadata{
+		Description: "Forecasts the weather",
+		Owner:       "Weather Team",
+		Tags:        []string{"forecasting", "external"},
+		DocURL:      "https://example.com/forecast",
+		SLA:         time.Nanosecond,
+	}
+}
+
+// Execute sets the ExecutionContext's Forecast value to "Sun"
+func (t *DocumentedForecast) Execute(ctx *ExecutionContext) error {
+	time.Sleep(time.Millisecond)
+	ctx.Set("Forecast", "Sun")
+	return nil
+}
+
+func TestMetadata(t *testing.T) {
+	gf := buildGraphflow()
+	documented := gf.AddTask(new(DocumentedForecast))
+
+	assert.Equal(t, "Weather Team", gf.Metadata(documented).Owner)
+	assert.True(t, gf.Metadata(documented).HasTag("Forecasting"))
+	assert.False(t, gf.Metadata(documented).HasTag("internal"))
+	assert.True(t, gf.Metadata(gf.Task("forecast-rain")).IsZero())
+
+	gf.SetMetadata(gf.Task("forecast-rain"), Metadata{Owner: "Rain Team"})
+
+	assert.Equal(t, "Rain Team", gf.Metadata(gf.Task("forecast-rain")).Owner)
+	clone := gf.Clone()
+	assert.Equal(t, "Rain Team", clone.Metadata(clone.Task("forecast-rain")).Owner)
+}
+
+func TestStepsRecordOwnerAndSLA(t *testing.T) {
+	ctx := new(ExecutionContext)
+	ctx.Set("Sky", "Clear")
+	gf := buildGraphflow()
+	gf.ReplaceTask(gf.Task("forecast-sun"), new(DocumentedForecast))
+
+	err := gf.Run(ctx)
+
+	assert.Nil(t, err)
+	step := gf.Result().Steps[2]
+	assert.Equal(t, "forecast-sun", step.TaskID)
+	assert.Equal(t, "Weather Team", step.Owner)
+	assert.True(t, step.OverSLA)
+	assert.Empty(t, gf.Result().Steps[1].Owner)
+	assert.False(t, gf.Result().Steps[1].OverSLA)
+}
+
+func TestMetadataInDefinitions(t *testing.T) {
+	registry := newTestRegistry()
+	gf, err := registry.LoadYAML(strings.NewReader(forecastYAML))
+	assert.Nil(t, err)
+	gf.SetMetadata(gf.Task("forecast-rain"), Metadata{
+		Owner: "Weather Team",
+		Tags:  []string{"forecasting"},
+		SLA:   5 * time.Second,
+	})
+
+	var buf bytes.Buffer
+	err = registry.WriteYAML(&buf, gf)
+
+	assert.Nil(t, err)
+	assert.Contains(t, buf.String(), `    metadata:
+      owner: Weather Team
+      tags:
+        - forecasting
+      sla: 5s
+`)
+
+	loaded, err := registry.LoadYAML(&buf)
+
+	assert.Nil(t, err)
+	assert.Equal(t, gf.Metadata(gf.Task("forecast-rain")), loaded.Metadata(loaded.Task("forecast-rain")))
+	assert.True(t, loaded.Metadata(loaded.Task("forecast-sun")).IsZero())
+}
@@ -0,0 +1,150 @@
+package graphflow
+
+import "fmt"
+
+// RemoveTask removes a Task from the graphflow, along with any Paths leading to or from it, its membership of any
+// TaskGroup and its use as a TaskGroup's error handler. Tasks that were only reachable through the removed Task are left in place.
+func (gf *Graphflow) RemoveTask(task TaskIntf) error {
+	if !gf.hasTask(task) {
+		return fmt.Errorf("Task %s hasn't been added to the graphflow", task)
+	}
+	tasks := []TaskIntf{}
+	for _, t := range gf.tasks {
+		if t != task {
+			tasks = append(tasks, t)
+		}
+	}
+	gf.tasks = tasks
+	delete(gf.ids, task)
+	delete(gf.metadata, task)
+	delete(gf.typeNames, task)
+	delete(gf.paths, task)
+	for from, edge := range gf.paths {
+		for condition, to := range edge {
+			if to == task {
+				delete(edge, condition)
+			}
+		}
+		if len(edge) == 0 {
+			delete(gf.paths, from)
+		}
+	}
+	gf.prunePathOrder()
+	for _, taskGroup := range gf.taskGroups {
+		tasks := []TaskIntf{}
+		for _, t := range taskGroup.tasks {
+			if t != task {
+				tasks = append(tasks, t)
+			}
+		}
+		taskGroup.tasks = tasks
+		if taskGroup.errorHandler == task {
+			taskGroup.errorHandler = nil
+		}
+	}
+	return nil
+}
+
+// RemovePath removes the Path leaving a Task with the given PathCondition
+func (gf *Graphflow) RemovePath(from TaskIntf, condition PathCondition) error {
+	if _, exists := gf.paths[from][condition]; !exists {
+		return fmt.Errorf("Task %s has no %s path", from, PathConditionName[condition])
+	}
+	delete(gf.paths[from], condition)
+	if len(gf.paths[from]) == 0 {
+		delete(gf.paths, from)
+	}
+	gf.prunePathOrder()
+	return nil
+}
+
+// ReplaceTask swaps one Task in the graphflow for a replacement that hasn't been added yet. The replacement takes over
+// the old Task's ID, position, Paths, TaskGroup, use as an error handler and any Metadata set with SetMetadata, so a
+// variant of a Task can be dropped into an existing graphflow.
+func (gf *Graphflow) ReplaceTask(old TaskIntf, replacement TaskIntf) error {
+	if !gf.hasTask(old) {
+		return fmt.Errorf("Task %s hasn't been added to the graphflow", old)
+	}
+	if gf.hasTask(replacement) {
+		return fmt.Errorf("Task %s has already been added to the graphflow", replacement)
+	}
+	for i, t := range gf.tasks {
+		if t == old {
+			gf.tasks[i] = replacement
+		}
+	}
+	gf.ids[replacement] = gf.ids[old]
+	delete(gf.ids, old)
+	if metadata, exists := gf.metadata[old]; exists {
+		gf.metadata[replacement] = metadata
+		delete(gf.metadata, old)
+	}
+	// the replacement may be of another type, so it's only recognised by its Go type
+	delete(gf.typeNames, old)
+	for i, key := range gf.pathOrder {
+		if key.from == old {
+			gf.pathOrder[i].from = replacement
+		}
+	}
+	if edge, exists := gf.paths[old]; exists {
+		gf.paths[replacement] = edge
+		delete(gf.paths, old)
+	}
+	for _, edge := range gf.paths {
+		for condition, to := range edge {
+			if to == old {
+				edge[condition] = replacement
+			}
+		}
+	}
+	for _, taskGroup := range gf.taskGroups {
+		for i, t := range taskGroup.tasks {
+			if t == old {
+				taskGroup.tasks[i] = replacement
+			}
+		}
+		if taskGroup.errorHandler == old {
+			taskGroup.errorHandler = replacement
+		}
+	}
+	return nil
+}
+
+// InsertBetween inserts a Task into an existing Path, so the Path leaving from with the given PathCondition leads
+// to task instead, and task has an ALWAYS Path to where it used to lead. The Task is added to the graphflow if it
+// hasn't been already, and can't have any Paths of its own.
+func (gf *Graphflow) InsertBetween(from TaskIntf, condition PathCondition, task TaskIntf) error {
+	to, exists := gf.paths[from][condition]
+	if !exists {
+		return fmt.Errorf("Task %s has no %s path", from, PathConditionName[condition])
+	}
+	if len(gf.paths[task]) > 0 {
+		return fmt.Errorf("Task %s already has paths, so it can't be inserted into another", task)
+	}
+	if !gf.hasTask(task) {
+		gf.AddTask(task)
+	}
+	gf.AddPath(from, condition, task)
+	gf.AddPath(task, ALWAYS, to)
+	return nil
+}
+
+// prunePathOrder forgets the order of Paths that have been removed, so they go to the end if they're added again
+func (gf *Graphflow) prunePathOrder() {
+	order := []pathKey{}
+	for _, key := range gf.pathOrder {
+		if _, exists := gf.paths[key.from][key.condition]; exists {
+			order = append(order, key)
+		}
+	}
+	gf.pathOrder = order
+}
+
+func (gf *Graphflow) hasTask(task TaskIntf) bool {
+	for _, t := range gf.tasks {
+		if t == task {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,108 @@
+package graphflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRemoveTask(t *testing.T) {
+	gf := buildGraphflow()
+	forecastRain := gf.Task("forecast-rain")
+	gf.NewTaskGroup("Forecasting").AddTasks(forecastRain, gf.Task("forecast-sun"))
+
+	err := gf.RemoveTask(forecastRain)
+
+	assert.Nil(t, err)
+	assert.Len(t, gf.Tasks(), 4)
+	assert.Nil(t, gf.Task("forecast-rain"))
+	assert.Nil(t, gf.Paths()[forecastRain])
+	assert.NotContains(t, gf.Paths()[gf.Task("is-the-sky-cloudy")], YES)
+	assert.Equal(t, []TaskIntf{gf.Task("forecast-sun")}, gf.TaskGroups()[0].Tasks())
+
+	err = gf.RemoveTask(forecastRain)
+
+	assert.NotNil(t, err)
+}
+
+func TestRemovePath(t *testing.T) {
+	gf := buildGraphflow()
+	isTheSkyCloudy := gf.Task("is-the-sky-cloudy")
+
+	err := gf.RemovePath(isTheSkyCloudy, YES)
+
+	assert.Nil(t, err)
+	assert.Len(t, gf.Paths()[isTheSkyCloudy], 1)
+	assert.NotNil(t, gf.Run(new(ExecutionContext))) // NO path without a YES path
+
+	err = gf.RemovePath(isTheSkyCloudy, YES)
+
+	assert.NotNil(t, err)
+}
+
+func TestReplaceTask(t *testing.T) {
+	ctx := new(ExecutionContext)
+	ctx.Set("Sky", "Clear")
+
+	gf := buildGraphflow()
+	forecastSun := gf.Task("forecast-sun")
+	gf.NewTaskGroup("Forecasting").AddTasks(forecastSun)
+	forecastNothing := new(TaskWithNoName)
+
+	err := gf.ReplaceTask(forecastSun, forecastNothing)
+
+	assert.Nil(t, err)
+	assert.Same(t, forecastNothing, gf.Task("forecast-sun"))
+	assert.Same(t, forecastNothing, gf.Tasks()[3])
+	assert.Equal(t, []TaskIntf{forecastNothing}, gf.TaskGroups()[0].Tasks())
+
+	err = gf.Run(ctx)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Nothing", ctx.Get("Forecast"))
+
+	err = gf.ReplaceTask(forecastSun, new(TaskWithNoName))
+
+	assert.NotNil(t, err)
+
+	err = gf.ReplaceTask(gf.Task("forecast-rain"), forecastNothing)
+
+	assert.NotNil(t, err)
+}
+
+func TestInsertBetween(t *testing.T) {
+	ctx := new(ExecutionContext)
+	ctx.Set("Sky", "Cloudy")
+
+	gf := buildGraphflow()
+	forecastRain := gf.Task("forecast-rain")
+	forecastNothing := new(TaskWithNoName)
+
+	err := gf.InsertBetween(forecastRain, ALWAYS, forecastNothing)
+
+	assert.Nil(t, err)
+	assert.Len(t, gf.Tasks(), 6)
+	assert.Same(t, forecastNothing, gf.Paths()[forecastRain][ALWAYS])
+	assert.Same(t, gf.Task("end"), gf.Paths()[forecastNothing][ALWAYS])
+
+	err = gf.Run(ctx)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Nothing", ctx.Get("Forecast"))
+
+	err = gf.InsertBetween(forecastRain, YES, new(TaskWithNoName))
+
+	assert.NotNil(t, err)
+}
+
+func TestInsertTaskWithPathsBetweenThrowsError(t *testing.T) {
+	gf := buildGraphflow()
+	start := gf.Task("start")
+	isTheSkyCloudy := gf.Task("is-the-sky-cloudy")
+
+	err := gf.InsertBetween(start, ALWAYS, isTheSkyCloudy)
+
+	assert.EqualError(t, err, "Task Is the sky cloudy? already has paths, so it can't be inserted into another")
+	assert.Same(t, isTheSkyCloudy, gf.Paths()[start][ALWAYS])
+	assert.NotContains(t, gf.Paths()[isTheSkyCloudy], ALWAYS)
+}
@@ -0,0 +1,127 @@
+package graphflow
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Policy sets how the Tasks in a TaskGroup are executed. The zero value executes each Task once, without a timeout.
+type Policy struct {
+	// Retries is the number of times a Task that returns an error is executed again before the error is handled.
+	// Retries, RetryDelay and Timeout can't be negative.
+	Retries int
+	// RetryDelay is how long to wait before each retry
+	RetryDelay time.Duration
+	// Timeout is how long a Task can take to execute before it fails with an error, or zero for no timeout. Tasks
+	// are never left running in the background: a Task that implements ContextExecutor is expected to stop once its
+	// context is done, and any other Task fails after it returns if it took longer than the Timeout.
+	Timeout time.Duration
+}
+
+// ContextExecutor can be implemented by Tasks that can be cancelled, such as Tasks that call other services. When
+// a Task implements it, ExecuteContext is called in place of Execute, with a context that's done when the Timeout
+// of the Task's Policy has passed.
+type ContextExecutor interface {
+	ExecuteContext(ctx context.Context, executionContext *ExecutionContext) error
+}
+
+// SetErrorHandler sets the Task that's executed when a Task in the TaskGroup, or in a TaskGroup nested inside it,
+// returns an error and has no ERROR Path of its own. The error handler needs to be added to the graphflow, and can
+// read the error with the ExecutionContext's Err method.
+func (t *TaskGroup) SetErrorHandler(task TaskIntf) {
+	t.errorHandler = task
+}
+
+// ErrorHandler returns the Task set with SetErrorHandler
+func (t *TaskGroup) ErrorHandler() TaskIntf {
+	return t.errorHandler
+}
+
+// SetPolicy sets the Policy for executing the Tasks in the TaskGroup, and in any TaskGroups nested inside it that
+// don't set a Policy of their own
+func (t *TaskGroup) SetPolicy(policy Policy) {
+	t.policy = policy
+}
+
+// Policy returns the Policy set with SetPolicy
+func (t *TaskGroup) Policy() Policy {
+	return t.policy
+}
+
+// taskGroupOf returns the TaskGroup a Task is in, or nil if it isn't in one
+func (gf *Graphflow) taskGroupOf(task TaskIntf) *TaskGroup {
+	for _, taskGroup := range gf.taskGroups {
+		for _, t := range taskGroup.tasks {
+			if t == task {
+				return taskGroup
+			}
+		}
+	}
+	return nil
+}
+
+// errorHandler returns the Task to execute next when a Task returns an error: the Task its ERROR Path leads to, or
+// else the error handler of the closest TaskGroup around it that has one. It returns nil if the error isn't handled.
+func (gf *Graphflow) errorHandler(task TaskIntf) TaskIntf {
+	if to, exists := gf.paths[task][ERROR]; exists {
+		return to
+	}
+	for taskGroup := gf.taskGroupOf(task); taskGroup != nil; taskGroup = taskGroup.parent {
+		if taskGroup.errorHandler != nil {
+			return taskGroup.errorHandler
+		}
+	}
+	return nil
+}
+
+// policy returns the Policy of the closest TaskGroup around a Task that sets one
+func (gf *Graphflow) policy(task TaskIntf) Policy {
+	for taskGroup := gf.taskGroupOf(task); taskGroup != nil; taskGroup = taskGroup.parent {
+		if taskGroup.policy != (Policy{}) {
+			return taskGroup.policy
+		}
+	}
+	return Policy{}
+}
+
+// executeTask executes a Task, retrying it and timing it out as set by the Policy of its TaskGroup. The Task's
+// ExitPath is reset to ALWAYS before each attempt, so the Path followed after an earlier error isn't followed again.
+func (gf *Graphflow) executeTask(task TaskIntf) error {
+	policy := gf.policy(task)
+	var err error
+	for attempt := 0; attempt <= policy.Retries; attempt++ {
+		if attempt > 0 && policy.RetryDelay > 0 {
+			time.Sleep(policy.RetryDelay)
+		}
+		task.SetExitPath(ALWAYS)
+		if err = gf.executeWithTimeout(task, policy.Timeout); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
+// executeWithTimeout executes a Task, failing with an error if it takes longer than the timeout. The Task is
+// executed on the calling goroutine, so a retry or the next Task never runs alongside it.
+func (gf *Graphflow) executeWithTimeout(task TaskIntf, timeout time.Duration) error {
+	executor, isContextExecutor := task.(ContextExecutor)
+	if timeout <= 0 {
+		if isContextExecutor {
+			return executor.ExecuteContext(context.Background(), gf.context)
+		}
+		return task.Execute(gf.context)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	var err error
+	if isContextExecutor {
+		err = executor.ExecuteContext(ctx, gf.context)
+	} else {
+		err = task.Execute(gf.context)
+	}
+	if ctx.Err() != nil {
+		return fmt.Errorf("Task %s timed out after %s", task, timeout)
+	}
+	return err
+}
@@ -0,0 +1,270 @@
+package graphflow
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// buildFailingGraphflow returns a graphflow whose sunny forecast fails the first time, with a Task in a
+// "Forecasting" TaskGroup that reports the error
+func buildFailingGraphflow() (*Graphflow, *TaskGroup) {
+	gf := buildGraphflow()
+	failOnce := new(FailOnce)
+	gf.InsertBetween(gf.Task("forecast-sun"), ALWAYS, failOnce)
+	reportFailure := gf.AddTask(ActionFunc("Report Failure", func(ctx *ExecutionContext) error {
+		ctx.Set("Forecast", fmt.Sprintf("Unknown: %s", ctx.Err()))
+		return nil
+	}))
+	gf.AddPath(reportFailure, ALWAYS, gf.Task("end"))
+	forecasting := gf.NewTaskGroup("Forecasting")
+	forecasting.AddTasks(gf.Task("forecast-sun"), failOnce)
+	forecasting.SetErrorHandler(reportFailure)
+	return gf, forecasting
+}
+
+func TestTaskGroupErrorHandler(t *testing.T) {
+	ctx := new(ExecutionContext)
+	ctx.Set("Sky", "Clear")
+	gf, _ := buildFailingGraphflow()
+
+	err := gf.Run(ctx)
+
+	assert.Nil(t, err)
+	assert.True(t, gf.Result().Ended)
+	assert.Equal(t, "Unknown: Temporary failure", ctx.Get("Forecast"))
+	step := gf.Result().Steps[3]
+	assert.Equal(t, "fail-once", step.TaskID)
+	assert.Equal(t, ERROR, step.ExitPath)
+	assert.Equal(t, "Temporary failure", step.Error)
+	assert.Equal(t, "report-failure", gf.Result().Steps[4].TaskID)
+}
+
+func TestErrorPathTakesPrecedenceOverErrorHandler(t *testing.T) {
+	ctx := new(ExecutionContext)
+	ctx.Set("Sky", "Clear")
+	gf, _ := buildFailingGraphflow()
+	gf.AddPath(gf.Task("fail-once"), ERROR, gf.Task("end"))
+
+	err := gf.Run(ctx)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Sun", ctx.Get("Forecast"))
+	assert.Equal(t, "end", gf.Result().Steps[4].TaskID)
+	assert.Equal(t, errors.New("Temporary failure"), ctx.Err())
+}
+
+func TestErrorIsOnlyFollowedOnTheRunThatFails(t *testing.T) {
+	gf, _ := buildFailingGraphflow()
+	ctx := new(ExecutionContext)
+	ctx.Set("Sky", "Clear")
+
+	assert.Nil(t, gf.Run(ctx))
+	assert.Equal(t, "Unknown: Temporary failure", ctx.Get("Forecast"))
+
+	ctx = new(ExecutionContext)
+	ctx.Set("Sky", "Clear")
+	err := gf.Run(ctx)
+
+	assert.Nil(t, err)
+	assert.True(t, gf.Result().Ended)
+	assert.Equal(t, "Recovered", ctx.Get("Forecast"))
+	assert.Equal(t, ALWAYS, gf.Result().Steps[3].ExitPath)
+}
+
+func TestErrorPathIsOnlyFollowedOnTheRunThatFails(t *testing.T) {
+	gf, forecasting := buildFailingGraphflow()
+	forecasting.SetErrorHandler(nil)
+	gf.AddPath(gf.Task("fail-once"), ERROR, gf.AddTask(NewEndTask("Failed")))
+	ctx := new(ExecutionContext)
+	ctx.Set("Sky", "Clear")
+
+	assert.Nil(t, gf.Run(ctx))
+	assert.Equal(t, "Failed", gf.Result().Outcome)
+
+	ctx = new(ExecutionContext)
+	ctx.Set("Sky", "Clear")
+	err := gf.Run(ctx)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "", gf.Result().Outcome)
+	assert.Equal(t, "Recovered", ctx.Get("Forecast"))
+}
+
+func TestNestedTaskGroupUsesParentErrorHandler(t *testing.T) {
+	ctx := new(ExecutionContext)
+	ctx.Set("Sky", "Clear")
+	gf, forecasting := buildFailingGraphflow()
+	forecasting.tasks = []TaskIntf{gf.Task("forecast-sun")}
+	forecasting.NewTaskGroup("Unreliable").AddTasks(gf.Task("fail-once"))
+
+	err := gf.Run(ctx)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Unknown: Temporary failure", ctx.Get("Forecast"))
+}
+
+func TestTaskGroupRetries(t *testing.T) {
+	ctx := new(ExecutionContext)
+	ctx.Set("Sky", "Clear")
+	gf, forecasting := buildFailingGraphflow()
+	forecasting.SetPolicy(Policy{Retries: 1, RetryDelay: time.Millisecond})
+
+	err := gf.Run(ctx)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Recovered", ctx.Get("Forecast"))
+	assert.Equal(t, ALWAYS, gf.Result().Steps[3].ExitPath)
+	assert.Empty(t, gf.Result().Steps[3].Error)
+}
+
+// SlowForecast is a Task struct that waits for a forecast until its context is done
+type SlowForecast struct {
+	Task
+}
+
+// String returns a description of the Task
+func (t *SlowForecast) String() string {
+	return "Slow Forecast"
+}
+
+// Execute waits for a forecast without a timeout
+func (t *SlowForecast) Execute(ctx *ExecutionContext) error {
+	return t.ExecuteContext(context.Background(), ctx)
+}
+
+// ExecuteContext waits for a forecast until the context is done
+func (t *SlowForecast) ExecuteContext(ctx context.Context, executionContext *ExecutionContext) error {
+	select {
+	case <-time.After(time.Minute):
+		executionContext.Set("Forecast", "Late")
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func TestTaskGroupTimeout(t *testing.T) {
+	ctx := new(ExecutionContext)
+	ctx.Set("Sky", "Clear")
+	gf, forecasting := buildFailingGraphflow()
+	slow := new(SlowForecast)
+	gf.InsertBetween(gf.Task("forecast-sun"), ALWAYS, slow)
+	forecasting.AddTasks(slow)
+	forecasting.SetPolicy(Policy{Timeout: 10 * time.Millisecond})
+
+	err := gf.Run(ctx)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "Unknown: Task Slow Forecast timed out after 10ms", ctx.Get("Forecast"))
+}
+
+// TestTaskGroupTimeoutWaitsForTask checks, when run with -race, that a Task that ignores its timeout isn't still
+// changing the ExecutionContext while it's retried or the error handler is executed
+func TestTaskGroupTimeoutWaitsForTask(t *testing.T) {
+	ctx := new(ExecutionContext)
+	ctx.Set("Sky", "Clear")
+	gf, forecasting := buildFailingGraphflow()
+	attempts := 0
+	slow := ActionFunc("Slow Forecast", func(ctx *ExecutionContext) error {
+		attempts++
+		time.Sleep(20 * time.Millisecond)
+		ctx.Set("Forecast", "Late")
+		return nil
+	})
+	gf.InsertBetween(gf.Task("forecast-sun"), ALWAYS, slow)
+	forecasting.AddTasks(slow)
+	forecasting.SetPolicy(Policy{Retries: 1, Timeout: time.Millisecond})
+
+	err := gf.Run(ctx)
+
+	assert.Nil(t, err)
+	assert.Equal(t, 2, attempts)
+	assert.Equal(t, "Unknown: Task Slow Forecast timed out after 1ms", ctx.Get("Forecast"))
+}
+
+func TestNegativePolicyThrowsError(t *testing.T) {
+	for _, policy := range []Policy{{Retries: -1}, {RetryDelay: -time.Second}, {Timeout: -time.Second}} {
+		ctx := new(ExecutionContext)
+		ctx.Set("Sky", "Clear")
+		gf, forecasting := buildFailingGraphflow()
+		forecasting.SetPolicy(policy)
+
+		err := gf.Run(ctx)
+
+		assert.EqualError(t, err, "TaskGroup \"Forecasting\" has a Policy with a negative number of retries, retry delay or timeout")
+		assert.Nil(t, ctx.Get("Forecast"))
+	}
+
+	registry := newTestRegistry()
+	gf, err := registry.LoadYAML(strings.NewReader(forecastYAML))
+	assert.Nil(t, err)
+	def, err := registry.Define(gf)
+	assert.Nil(t, err)
+	def.Groups[0].Retries = -1
+
+	_, err = registry.Build(def)
+
+	assert.EqualError(t, err, "TaskGroup \"Forecasting\" has a Policy with a negative number of retries, retry delay or timeout")
+}
+
+func TestUnhandledErrorsStillStopTheRun(t *testing.T) {
+	ctx := new(ExecutionContext)
+	ctx.Set("Sky", "Clear")
+	gf, forecasting := buildFailingGraphflow()
+	forecasting.SetErrorHandler(nil)
+
+	err := gf.Run(ctx)
+
+	assert.EqualError(t, err, "Temporary failure")
+	assert.Equal(t, "fail-once", gf.Result().Next)
+}
+
+func TestErrorHandlerNotInGraphflowThrowsError(t *testing.T) {
+	gf, forecasting := buildFailingGraphflow()
+	forecasting.SetErrorHandler(new(ForecastRain))
+
+	err := gf.Run(new(ExecutionContext))
+
+	assert.EqualError(t, err, "TaskGroup \"Forecasting\" has an error handler Forecast Rain which hasn't been added to the graphflow")
+}
+
+func TestRemovingErrorHandlerClearsIt(t *testing.T) {
+	gf, forecasting := buildFailingGraphflow()
+
+	assert.Nil(t, gf.RemoveTask(gf.Task("report-failure")))
+
+	assert.Nil(t, forecasting.ErrorHandler())
+}
+
+func TestTaskGroupPoliciesRoundTrip(t *testing.T) {
+	registry := newTestRegistry()
+	gf, err := registry.LoadYAML(strings.NewReader(forecastYAML))
+	assert.Nil(t, err)
+	forecasting := gf.TaskGroups()[0]
+	forecasting.SetErrorHandler(gf.Task("end"))
+	forecasting.SetPolicy(Policy{Retries: 2, RetryDelay: 500 * time.Millisecond, Timeout: time.Minute})
+
+	def, err := registry.Define(gf)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "end", def.Groups[0].ErrorHandler)
+	assert.Equal(t, "500ms", def.Groups[0].RetryDelay)
+	assert.Equal(t, "1m0s", def.Groups[0].Timeout)
+
+	loaded, err := registry.Build(def)
+
+	assert.Nil(t, err)
+	assert.Same(t, loaded.Task("end"), loaded.TaskGroups()[0].ErrorHandler())
+	assert.Equal(t, forecasting.Policy(), loaded.TaskGroups()[0].Policy())
+
+	def.Groups[0].Timeout = "soon"
+	_, err = registry.Build(def)
+
+	assert.EqualError(t, err, "Group \"Forecasting\" has an invalid timeout: time: invalid duration \"soon\"")
+}
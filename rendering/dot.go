@@ -0,0 +1,149 @@
+package rendering
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/futrli/graphflow"
+)
+
+// WriteDOT writes Graphviz DOT source for the graphflow, with the same colours, clusters, labels, tooltips and SVG IDs
+// as RenderGraph, without needing cgo. The output is the same every time for the same graphflow, so it can be committed and diffed,
+// and rendered with any Graphviz tool (eg dot -Tsvg).
+func WriteDOT(w io.Writer, gf *graphflow.Graphflow, opts DOTOptions) error {
+	path, description := opts.path(gf)
+	return writeDOT(w, newGraphModel(gf, path, description, opts))
+}
+
+func writeDOT(w io.Writer, m *graphModel) error {
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "digraph {")
+	writeDOTAttrs(bw, m)
+	for _, i := range m.children(-1) {
+		writeDOTCluster(bw, "\t", m, i)
+	}
+	for _, n := range m.nodes {
+		if n.cluster < 0 {
+			writeDOTNode(bw, "\t", n)
+		}
+	}
+	if m.description != "" {
+		fmt.Fprintf(bw, "\t%s [shape=\"underline\", margin=\"0.2\"", quote(m.description))
+		if m.theme.Font != "" {
+			fmt.Fprintf(bw, ", fontcolor=%s", quote(m.theme.Font))
+		}
+		fmt.Fprintln(bw, "];")
+	}
+	for _, e := range m.edges {
+		fmt.Fprintf(bw, "\t%s -> %s", quote(e.from), quote(e.to))
+		attrs := []string{}
+		if label := e.fullLabel(); label != "" {
+			attrs = append(attrs, fmt.Sprintf("label=%s", quote(label)))
+		}
+		if e.taken {
+			attrs = append(attrs, "penwidth=\"2\"")
+		}
+		if e.dashed {
+			attrs = append(attrs, "style=\"dashed\"")
+		}
+		attrs = append(attrs, fmt.Sprintf("id=%s", quote(e.id())))
+		fmt.Fprintf(bw, " [%s];\n", strings.Join(attrs, ", "))
+	}
+	fmt.Fprintln(bw, "}")
+	return bw.Flush()
+}
+
+// writeDOTAttrs writes the attributes of the graph and its default node and edge attributes, where they've been set
+func writeDOTAttrs(w io.Writer, m *graphModel) {
+	graphAttrs := []string{}
+	nodeAttrs := []string{}
+	edgeAttrs := []string{}
+	if m.rankDir != "" {
+		graphAttrs = append(graphAttrs, fmt.Sprintf("rankdir=%s", quote(string(m.rankDir))))
+	}
+	if m.dpi > 0 {
+		graphAttrs = append(graphAttrs, fmt.Sprintf("dpi=\"%g\"", m.dpi))
+	}
+	if m.theme.Background != "" {
+		graphAttrs = append(graphAttrs, fmt.Sprintf("bgcolor=%s", quote(m.theme.Background)))
+	}
+	if m.theme.Font != "" {
+		graphAttrs = append(graphAttrs, fmt.Sprintf("fontcolor=%s", quote(m.theme.Font)))
+		edgeAttrs = append(edgeAttrs, fmt.Sprintf("fontcolor=%s", quote(m.theme.Font)))
+	}
+	if m.theme.Edge != "" {
+		edgeAttrs = append(edgeAttrs, fmt.Sprintf("color=%s", quote(m.theme.Edge)))
+	}
+	if m.fontName != "" {
+		for _, attrs := range []*[]string{&graphAttrs, &nodeAttrs, &edgeAttrs} {
+			*attrs = append(*attrs, fmt.Sprintf("fontname=%s", quote(m.fontName)))
+		}
+	}
+	for _, attrs := range []struct {
+		name   string
+		values []string
+	}{{"graph", graphAttrs}, {"node", nodeAttrs}, {"edge", edgeAttrs}} {
+		if len(attrs.values) > 0 {
+			fmt.Fprintf(w, "\t%s [%s];\n", attrs.name, strings.Join(attrs.values, ", "))
+		}
+	}
+}
+
+func writeDOTNode(w io.Writer, indent string, n *nodeModel) {
+	attrs := []string{
+		fmt.Sprintf("label=%s", quote(n.fullLabel())),
+		"style=\"filled\"",
+	}
+	if n.colorScheme != "" {
+		attrs = append(attrs, fmt.Sprintf("colorscheme=%s", quote(n.colorScheme)))
+	}
+	attrs = append(attrs, fmt.Sprintf("color=%s", quote(n.color)))
+	if n.shape != "" {
+		attrs = append(attrs, fmt.Sprintf("shape=%s", quote(n.shape)))
+	}
+	if n.fontColor != "" {
+		attrs = append(attrs, fmt.Sprintf("fontcolor=%s", quote(n.fontColor)))
+	}
+	if n.url != "" {
+		attrs = append(attrs, fmt.Sprintf("URL=%s", quote(n.url)))
+	}
+	if n.tooltip != "" {
+		attrs = append(attrs, fmt.Sprintf("tooltip=%s", quote(n.tooltip)))
+	}
+	attrs = append(attrs, fmt.Sprintf("id=%s", quote(nodeID(n.id))))
+	fmt.Fprintf(w, "%s%s [%s];\n", indent, quote(n.id), strings.Join(attrs, ", "))
+}
+
+// writeDOTCluster writes a TaskGroup as a subgraph containing its Tasks and the TaskGroups nested inside it
+func writeDOTCluster(w io.Writer, indent string, m *graphModel, cluster int) {
+	c := m.clusters[cluster]
+	fmt.Fprintf(w, "%ssubgraph %s {\n", indent, quote(fmt.Sprintf("cluster_%d", cluster)))
+	fmt.Fprintf(w, "%s\tlabel=%s;\n", indent, quote(c.name))
+	fmt.Fprintf(w, "%s\tlabeljust=\"l\";\n", indent)
+	fmt.Fprintf(w, "%s\tstyle=\"filled\";\n", indent)
+	if bgColor := c.bgColor(m.theme); bgColor != "" {
+		fmt.Fprintf(w, "%s\tbgcolor=%s;\n", indent, quote(bgColor))
+	}
+	if c.description != "" {
+		fmt.Fprintf(w, "%s\ttooltip=%s;\n", indent, quote(c.description))
+	}
+	for _, i := range m.children(cluster) {
+		writeDOTCluster(w, indent+"\t", m, i)
+	}
+	for _, n := range m.nodes {
+		if n.cluster == cluster {
+			writeDOTNode(w, indent+"\t", n)
+		}
+	}
+	fmt.Fprintf(w, "%s}\n", indent)
+}
+
+// quote returns s as a double-quoted DOT string
+func quote(s string) string {
+	s = strings.ReplaceAll(s, "\\", "\\\\")
+	s = strings.ReplaceAll(s, "\"", "\\\"")
+	s = strings.ReplaceAll(s, "\n", "\\n")
+	return fmt.Sprintf("\"%s\"", s)
+}
@@ -0,0 +1,217 @@
+package rendering
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/futrli/graphflow"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteDOT(t *testing.T) {
+	gf := buildGraphflow()
+	gf.NewTaskGroup("Forecasting").AddTasks(gf.Task("forecast-rain"), gf.Task("forecast-sun"))
+
+	var buf bytes.Buffer
+	err := WriteDOT(&buf, gf, DOTOptions{})
+
+	assert.Nil(t, err)
+	assert.Equal(t, `digraph {
+	subgraph "cluster_0" {
+		label="Forecasting";
+		labeljust="l";
+		style="filled";
+		bgcolor="lightgrey";
+		"forecast-rain" [label="Forecast Rain", style="filled", colorscheme="paired10", color="9", tooltip="Forecast Rain (forecast-rain)", id="task-forecast-rain"];
+		"forecast-sun" [label="Forecast Sun", style="filled", colorscheme="paired10", color="9", tooltip="Forecast Sun (forecast-sun)", id="task-forecast-sun"];
+	}
+	"start" [label="Start", style="filled", colorscheme="paired10", color="7", tooltip="Start (start)", id="task-start"];
+	"is-the-sky-cloudy" [label="Is the sky cloudy?", style="filled", colorscheme="paired10", color="3", tooltip="Is the sky cloudy? (is-the-sky-cloudy)", id="task-is-the-sky-cloudy"];
+	"end" [label="End", style="filled", colorscheme="paired10", color="7", tooltip="End (end)", id="task-end"];
+	"start" -> "is-the-sky-cloudy" [id="path-start:ALWAYS"];
+	"is-the-sky-cloudy" -> "forecast-rain" [label="YES", id="path-is-the-sky-cloudy:YES"];
+	"is-the-sky-cloudy" -> "forecast-sun" [label="NO", id="path-is-the-sky-cloudy:NO"];
+	"forecast-rain" -> "end" [id="path-forecast-rain:ALWAYS"];
+	"forecast-sun" -> "end" [id="path-forecast-sun:ALWAYS"];
+}
+`, buf.String())
+}
+
+func TestWriteDOTShowPath(t *testing.T) {
+	ctx := new(graphflow.ExecutionContext)
+	ctx.Set("Sky", "Clear")
+	gf := buildGraphflow()
+	gf.Run(ctx)
+
+	var buf bytes.Buffer
+	err := WriteDOT(&buf, gf, DOTOptions{ShowPath: true, ContextKeys: []string{"Sky"}})
+
+	assert.Nil(t, err)
+	assert.Contains(t, buf.String(), `"forecast-rain" [label="Forecast Rain", style="filled", colorscheme="greys3", color="1", fontcolor="2", tooltip="Forecast Rain (forecast-rain)", id="task-forecast-rain"];`)
+	assert.Regexp(t, `"forecast-sun" \[label="Forecast Sun\\n[0-9.]+[nµm]?s", style="filled", colorscheme="paired10", color="9", tooltip="Forecast Sun \(forecast-sun\)", id="task-forecast-sun"\];`, buf.String())
+	assert.Contains(t, buf.String(), `"is-the-sky-cloudy" -> "forecast-sun" [label="#2 NO", penwidth="2", id="path-is-the-sky-cloudy:NO"];`)
+	assert.Contains(t, buf.String(), `"This is the path taken when:\n\nSky = Clear" [shape="underline", margin="0.2"];`)
+}
+
+func TestWriteDOTIsDeterministic(t *testing.T) {
+	var first bytes.Buffer
+	WriteDOT(&first, buildGraphflow(), DOTOptions{})
+
+	for i := 0; i < 20; i++ {
+		var buf bytes.Buffer
+		WriteDOT(&buf, buildGraphflow(), DOTOptions{})
+		assert.Equal(t, first.String(), buf.String())
+	}
+}
+
+func TestWriteDOTEmitsPathsInInsertionOrder(t *testing.T) {
+	gf := new(graphflow.Graphflow)
+	start := gf.AddTask(new(graphflow.StartTask))
+	isTheSkyCloudy := gf.AddTask(new(IsTheSkyCloudy))
+	forecastRain := gf.AddTask(new(ForecastRain))
+	forecastSun := gf.AddTask(new(ForecastSun))
+	end := gf.AddTask(new(graphflow.EndTask))
+	gf.AddPath(forecastSun, graphflow.ALWAYS, end)
+	gf.AddPath(forecastRain, graphflow.ALWAYS, end)
+	gf.AddPath(isTheSkyCloudy, graphflow.NO, forecastSun)
+	gf.AddPath(isTheSkyCloudy, graphflow.YES, forecastRain)
+	gf.AddPath(start, graphflow.ALWAYS, isTheSkyCloudy)
+
+	var buf bytes.Buffer
+	err := WriteDOT(&buf, gf, DOTOptions{})
+
+	assert.Nil(t, err)
+	assert.Contains(t, buf.String(), `	"forecast-sun" -> "end" [id="path-forecast-sun:ALWAYS"];
+	"forecast-rain" -> "end" [id="path-forecast-rain:ALWAYS"];
+	"is-the-sky-cloudy" -> "forecast-sun" [label="NO", id="path-is-the-sky-cloudy:NO"];
+	"is-the-sky-cloudy" -> "forecast-rain" [label="YES", id="path-is-the-sky-cloudy:YES"];
+	"start" -> "is-the-sky-cloudy" [id="path-start:ALWAYS"];
+}
+`)
+}
+
+// FailingForecast is a Task struct that always fails
+type FailingForecast struct {
+	graphflow.Task
+}
+
+// String returns a description of the Task
+func (t *FailingForecast) String() string {
+	return "Failing Forecast"
+}
+
+// Execute returns an error
+func (t *FailingForecast) Execute(ctx *graphflow.ExecutionContext) error {
+	return errors.New("No satellite data")
+}
+
+func TestWriteDOTShowsFailedTask(t *testing.T) {
+	ctx := new(graphflow.ExecutionContext)
+	ctx.Set("Sky", "Clear")
+	gf := buildGraphflow()
+	gf.InsertBetween(gf.Task("forecast-sun"), graphflow.ALWAYS, new(FailingForecast))
+	assert.NotNil(t, gf.Run(ctx))
+
+	var buf bytes.Buffer
+	err := WriteDOT(&buf, gf, DOTOptions{ShowPath: true})
+
+	assert.Nil(t, err)
+	assert.Contains(t, buf.String(), `"failing-forecast" [label="Failing Forecast\nFailed: No satellite data", style="filled", colorscheme="paired10", color="6", tooltip="Failing Forecast (failing-forecast)", id="task-failing-forecast"];`)
+	assert.Contains(t, buf.String(), `"forecast-sun" -> "failing-forecast" [label="#3", penwidth="2", id="path-forecast-sun:ALWAYS"];`)
+	assert.Contains(t, buf.String(), `"failing-forecast" -> "end" [id="path-failing-forecast:ALWAYS"];`)
+}
+
+func TestTracedPathShowsDurations(t *testing.T) {
+	gf := buildGraphflow()
+	result := &graphflow.RunResult{
+		Ended: true,
+		Steps: []graphflow.Step{
+			{TaskID: "start", ExitPath: graphflow.ALWAYS, Duration: time.Millisecond},
+			{TaskID: "is-the-sky-cloudy", ExitPath: graphflow.NO, Duration: 1234567 * time.Nanosecond},
+			{TaskID: "forecast-sun", ExitPath: graphflow.ALWAYS, Duration: 2 * time.Second},
+			{TaskID: "end", ExitPath: graphflow.ALWAYS},
+		},
+	}
+
+	var buf bytes.Buffer
+	err := writeDOT(&buf, newGraphModel(gf, tracedPath(result), "", RenderOptions{}))
+
+	assert.Nil(t, err)
+	assert.Contains(t, buf.String(), `"is-the-sky-cloudy" [label="Is the sky cloudy?\n1.23ms"`)
+	assert.Contains(t, buf.String(), `"forecast-sun" [label="Forecast Sun\n2s"`)
+	assert.Contains(t, buf.String(), `"end" [label="End", style="filled"`)
+	assert.Contains(t, buf.String(), `"is-the-sky-cloudy" -> "forecast-sun" [label="#2 NO", penwidth="2", id="path-is-the-sky-cloudy:NO"];`)
+}
+
+func TestWriteDOTNestsTaskGroups(t *testing.T) {
+	gf := buildGraphflow()
+	forecasting := gf.NewTaskGroup("Forecasting")
+	forecasting.SetDescription("Decides what to forecast")
+	forecasting.AddTasks(gf.Task("is-the-sky-cloudy"))
+	rain := forecasting.NewTaskGroup("Rain")
+	rain.SetColor("lightblue")
+	rain.AddTasks(gf.Task("forecast-rain"))
+
+	var buf bytes.Buffer
+	err := WriteDOT(&buf, gf, DOTOptions{})
+
+	assert.Nil(t, err)
+	assert.Contains(t, buf.String(), `	subgraph "cluster_0" {
+		label="Forecasting";
+		labeljust="l";
+		style="filled";
+		bgcolor="lightgrey";
+		tooltip="Decides what to forecast";
+		subgraph "cluster_1" {
+			label="Rain";
+			labeljust="l";
+			style="filled";
+			bgcolor="lightblue";
+			"forecast-rain" [label="Forecast Rain", style="filled", colorscheme="paired10", color="9", tooltip="Forecast Rain (forecast-rain)", id="task-forecast-rain"];
+		}
+		"is-the-sky-cloudy" [label="Is the sky cloudy?", style="filled", colorscheme="paired10", color="3", tooltip="Is the sky cloudy? (is-the-sky-cloudy)", id="task-is-the-sky-cloudy"];
+	}
+`)
+}
+
+func TestWriteDOTKeepsTaskGroupsWithTheSameNameApart(t *testing.T) {
+	gf := buildGraphflow()
+	gf.NewTaskGroup("Rain").NewTaskGroup("Checks").AddTasks(gf.Task("forecast-rain"))
+	gf.NewTaskGroup("Sun").NewTaskGroup("Checks").AddTasks(gf.Task("forecast-sun"))
+	gf.NewTaskGroup("Legend").AddTasks(gf.Task("is-the-sky-cloudy"))
+
+	var buf bytes.Buffer
+	err := WriteDOT(&buf, gf, DOTOptions{Legend: true})
+
+	assert.Nil(t, err)
+	dot := buf.String()
+	for i := 0; i < 6; i++ {
+		assert.Contains(t, dot, fmt.Sprintf("subgraph \"cluster_%d\" {", i))
+	}
+	assert.Equal(t, 2, strings.Count(dot, "label=\"Checks\";"))
+	assert.Equal(t, 2, strings.Count(dot, "label=\"Legend\";"))
+}
+
+func TestWriteDOTShowsCoverage(t *testing.T) {
+	gf := buildGraphflow()
+	coverage := graphflow.NewCoverage(gf)
+	gf.CollectCoverage(coverage)
+	ctx := new(graphflow.ExecutionContext)
+	ctx.Set("Sky", "Cloudy")
+	assert.Nil(t, gf.Run(ctx))
+
+	var buf bytes.Buffer
+	err := WriteDOT(&buf, gf, DOTOptions{Coverage: coverage.Report()})
+
+	assert.Nil(t, err)
+	dot := buf.String()
+	assert.Contains(t, dot, `"Coverage of 1 runs: 80.0% of Tasks, 60.0% of Paths" [shape="underline"`)
+	assert.Contains(t, dot, `"forecast-rain" [label="Forecast Rain\n×1", style="filled", colorscheme="paired10", color="9", tooltip="Forecast Rain (forecast-rain)", id="task-forecast-rain"];`)
+	assert.Contains(t, dot, `"forecast-sun" [label="Forecast Sun\n×0", style="filled", colorscheme="paired10", color="6", tooltip="Forecast Sun (forecast-sun)", id="task-forecast-sun"];`)
+	assert.Contains(t, dot, `"is-the-sky-cloudy" -> "forecast-rain" [label="×1 YES", penwidth="2", id="path-is-the-sky-cloudy:YES"];`)
+	assert.Contains(t, dot, `"is-the-sky-cloudy" -> "forecast-sun" [label="×0 NO", style="dashed", id="path-is-the-sky-cloudy:NO"];`)
+}
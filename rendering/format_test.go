@@ -0,0 +1,179 @@
+package rendering
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/futrli/graphflow"
+	"github.com/stretchr/testify/assert"
+)
+
+// DocumentedForecast is a Task struct that links to its documentation
+type DocumentedForecast struct {
+	graphflow.Task
+}
+
+// String returns a description of the Task
+func (t *DocumentedForecast) String() string {
+	return "Documented Forecast"
+}
+
+// URL returns the address of the Task's documentation
+func (t *DocumentedForecast) URL() string {
+	return "https://example.com/forecast"
+}
+
+func buildLinkedGraphflow() *graphflow.Graphflow {
+	gf := new(graphflow.Graphflow)
+	start := gf.AddTask(new(graphflow.StartTask))
+	forecast := gf.AddTask(new(DocumentedForecast))
+	end := gf.AddTask(new(graphflow.EndTask))
+	gf.AddPath(start, graphflow.ALWAYS, forecast)
+	gf.AddPath(forecast, graphflow.ALWAYS, end)
+	return gf
+}
+
+func TestRenderGraphAsSVG(t *testing.T) {
+	gf := buildLinkedGraphflow()
+
+	buf, err := RenderGraphAs(gf, SVG)
+
+	assert.Nil(t, err)
+	svg := buf.String()
+	assert.Contains(t, svg, "<svg")
+	assert.Contains(t, svg, "<g id=\"task&#45;start\" class=\"node\">")
+	assert.Contains(t, svg, "xlink:title=\"End (end)\"")
+	assert.Contains(t, svg, "xlink:href=\"https://example.com/forecast\"")
+}
+
+func TestRenderGraphAsPDF(t *testing.T) {
+	gf := buildLinkedGraphflow()
+	gf.NewTaskGroup("Forecasting (daily)").AddTasks(gf.Task("documented-forecast"))
+
+	buf, err := RenderGraphAs(gf, PDF)
+
+	assert.Nil(t, err)
+	pdf := buf.String()
+	assert.True(t, strings.HasPrefix(pdf, "%PDF-"))
+	assert.True(t, strings.HasSuffix(pdf, "%%EOF\n"))
+	assert.NotContains(t, pdf, "/Image")
+	assert.Contains(t, pdf, "/BaseFont /Times-Roman /Encoding /WinAnsiEncoding")
+	assert.Contains(t, pdf, "/F1 14 Tf")
+	assert.Contains(t, pdf, "(Start) Tj")
+	assert.Contains(t, pdf, "(Forecasting \\(daily\\)) Tj")
+	assert.Contains(t, pdf, "/A << /S /URI /URI (https://example.com/forecast) >>")
+}
+
+func TestPDFString(t *testing.T) {
+	assert.Equal(t, `(Forecast\\Sun \327 2 \(50\226100%\) ?)`, pdfString("Forecast\\Sun × 2 (50–100%) ☂"))
+}
+
+func TestRenderGraphAsJPEG(t *testing.T) {
+	gf := buildGraphflow()
+
+	buf, err := RenderGraphAs(gf, JPEG)
+
+	assert.Nil(t, err)
+	assert.True(t, bytes.HasPrefix(buf.Bytes(), []byte{0xff, 0xd8}))
+}
+
+func TestRenderGraphAsDOT(t *testing.T) {
+	gf := buildLinkedGraphflow()
+
+	buf, err := RenderGraphAs(gf, DOT)
+
+	assert.Nil(t, err)
+	var expected bytes.Buffer
+	assert.Nil(t, WriteDOT(&expected, gf, DOTOptions{}))
+	assert.Equal(t, expected.String(), buf.String())
+	assert.Contains(t, buf.String(), "URL=\"https://example.com/forecast\"")
+}
+
+func TestRenderPathThroughGraphAsSVG(t *testing.T) {
+	ctx := new(graphflow.ExecutionContext)
+	ctx.Set("Sky", "Cloudy")
+	gf := buildGraphflow()
+	assert.Nil(t, gf.Run(ctx))
+
+	buf, err := RenderPathThroughGraphAs(ctx, gf, SVG, "Sky")
+
+	assert.Nil(t, err)
+	assert.Contains(t, buf.String(), "Sky = Cloudy")
+}
+
+func TestRenderGraphAsUnsupportedFormat(t *testing.T) {
+	gf := buildGraphflow()
+
+	_, err := RenderGraphAs(gf, Format("gif"))
+
+	assert.EqualError(t, err, "Rendering format \"gif\" isn't supported")
+}
+
+func TestRenderingIsByteIdentical(t *testing.T) {
+	for _, format := range []Format{PNG, SVG, DOT} {
+		gf := buildGraphflow()
+		gf.NewTaskGroup("Forecasting").AddTasks(gf.Task("forecast-rain"), gf.Task("forecast-sun"))
+		first, err := RenderGraphAs(gf, format)
+		assert.Nil(t, err)
+
+		for i := 0; i < 10; i++ {
+			gf := buildGraphflow()
+			gf.NewTaskGroup("Forecasting").AddTasks(gf.Task("forecast-rain"), gf.Task("forecast-sun"))
+			buf, err := RenderGraphAs(gf, format)
+			assert.Nil(t, err)
+			assert.True(t, bytes.Equal(first.Bytes(), buf.Bytes()), "%s output differs between renders", format)
+		}
+	}
+}
+
+func TestRenderFailedRun(t *testing.T) {
+	ctx := new(graphflow.ExecutionContext)
+	ctx.Set("Sky", "Cloudy")
+	gf := buildGraphflow()
+	gf.InsertBetween(gf.Task("forecast-rain"), graphflow.ALWAYS, new(FailingForecast))
+	assert.NotNil(t, gf.Run(ctx))
+
+	buf, err := Render(gf, RenderOptions{Format: SVG, ShowPath: true})
+
+	assert.Nil(t, err)
+	assert.Contains(t, buf.String(), "Failed: No satellite data")
+	assert.Contains(t, buf.String(), ">#2 YES<")
+}
+
+func TestRenderRunFromStorage(t *testing.T) {
+	ctx := new(graphflow.ExecutionContext)
+	ctx.Set("Sky", "Cloudy")
+	gf := buildGraphflow()
+	gf.RecordContext(true)
+	assert.Nil(t, gf.Run(ctx))
+	stored, err := json.Marshal(gf.Result())
+	assert.Nil(t, err)
+
+	// render the stored run on a fresh graphflow, without running it
+	var result graphflow.RunResult
+	assert.Nil(t, json.Unmarshal(stored, &result))
+	fresh := buildGraphflow()
+	buf, err := RenderRun(fresh, &result, "Sky")
+
+	assert.Nil(t, err)
+	assert.NotEmpty(t, buf.Bytes())
+	assert.Nil(t, fresh.Result())
+
+	dot, err := Render(fresh, RenderOptions{Format: DOT, Run: &result, ContextKeys: []string{"Sky"}})
+
+	assert.Nil(t, err)
+	assert.Contains(t, dot.String(), `"is-the-sky-cloudy" -> "forecast-rain" [label="#2 YES", penwidth="2", id="path-is-the-sky-cloudy:YES"];`)
+	assert.Contains(t, dot.String(), `"This is the path taken when:\n\nSky = Cloudy"`)
+}
+
+func TestRenderCoverage(t *testing.T) {
+	gf := buildGraphflow()
+	coverage := graphflow.NewCoverage(gf)
+
+	buf, err := RenderCoverage(gf, coverage.Report())
+
+	assert.Nil(t, err)
+	assert.NotEmpty(t, buf.Bytes())
+}
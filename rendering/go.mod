@@ -3,7 +3,7 @@ module github.com/futrli/graphflow/rendering
 go 1.21
 
 require (
-	github.com/futrli/graphflow v1.0.0-pre1
+	github.com/futrli/graphflow v1.1.0
 	github.com/goccy/go-graphviz v0.1.1
 	github.com/stretchr/testify v1.6.1
 )
@@ -15,5 +15,7 @@ require (
 	github.com/pkg/errors v0.9.1 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	golang.org/x/image v0.18.0 // indirect
-	gopkg.in/yaml.v3 v3.0.0-20200313102051-9f266ea9e77c // indirect
+	gopkg.in/yaml.v3 v3.0.0-20220521103104-8f96da9f5d5e // indirect
 )
+
+replace github.com/futrli/graphflow => ../
@@ -2,6 +2,7 @@ package rendering
 
 import (
 	"bytes"
+	"errors"
 	"fmt"
 	"github.com/futrli/graphflow"
 	"github.com/goccy/go-graphviz"
@@ -9,28 +10,228 @@ import (
 	"log"
 )
 
+// Format is an output format that a graphflow can be rendered in
+type Format string
+
+const (
+	// PNG renders a png image
+	PNG Format = "png"
+	// JPEG renders a jpeg image
+	JPEG Format = "jpg"
+	// SVG renders a scalable svg image, in which each Task has a tooltip and, if it implements Linker, a link
+	SVG Format = "svg"
+	// PDF renders a single page vector pdf document, so large graphs can be zoomed into, in which Tasks implementing
+	// Linker link to their URL. Text is drawn in the standard pdf font closest to the RenderOptions font.
+	PDF Format = "pdf"
+	// DOT renders Graphviz DOT source, as written by WriteDOT
+	DOT Format = "dot"
+)
+
 // RenderGraph returns a buffer of bytes containing a graphviz png representation of all the Tasks and the Paths
 // connecting them. Needs to be passed a GraphVizIntf implementation to render the graph (eg graphviz.New())
 func RenderGraph(gf *graphflow.Graphflow) (bytes.Buffer, error) {
-	return generateGraph(gf, false, "")
+	return RenderGraphAs(gf, PNG)
+}
+
+// RenderGraphAs behaves like RenderGraph but renders the graph in the given Format
+func RenderGraphAs(gf *graphflow.Graphflow, format Format) (bytes.Buffer, error) {
+	return Render(gf, RenderOptions{Format: format})
+}
+
+// Render returns a buffer of bytes containing a representation of the graphflow drawn as described by the
+// RenderOptions, highlighting the path of the Run or of the graphflow's most recent run if ShowPath is set.
+// Tasks are never executed by rendering.
+func Render(gf *graphflow.Graphflow, opts RenderOptions) (bytes.Buffer, error) {
+	path, description := opts.path(gf)
+	return generateGraph(gf, path, description, opts)
+}
+
+// RenderRun returns a buffer of bytes containing a graphviz png representation of the graphflow with the path recorded
+// in a RunResult highlighted. The RunResult can come from an earlier run, eg one unmarshalled from storage, and
+// Tasks are never executed. Any Context Keys provided are rendered with their values after the last step, if the
+// run was made with RecordContext set.
+func RenderRun(gf *graphflow.Graphflow, result *graphflow.RunResult, contextKeysToRender ...string) (bytes.Buffer, error) {
+	return Render(gf, RenderOptions{Run: result, ContextKeys: contextKeysToRender})
+}
+
+// RenderCoverage returns a buffer of bytes containing a graphviz png representation of the graphflow showing the
+// coverage collected from many runs, with the Tasks and Paths that were never covered picked out
+func RenderCoverage(gf *graphflow.Graphflow, report *graphflow.CoverageReport) (bytes.Buffer, error) {
+	return Render(gf, RenderOptions{Coverage: report})
 }
 
 // RenderPathThroughGraph returns a buffer of bytes containing a graphviz png representation of all the Tasks and the Paths
-// connecting them, with the actual path taken for the given ExecutionContext highlighted. Any Context Keys provided will be
-// rendered with their values at the top of the image.
+// connecting them, with the path taken by the most recent Run of the graphflow highlighted. Any Context Keys provided will be
+// rendered with their values in the given ExecutionContext at the top of the image. The graphflow needs to have been run
+// first, as rendering never executes Tasks.
 func RenderPathThroughGraph(context *graphflow.ExecutionContext, gf *graphflow.Graphflow, contextKeysToRender ...string) (bytes.Buffer, error) {
-	if len(gf.Executed()) == 0 {
-		err := gf.Run(context)
+	return RenderPathThroughGraphAs(context, gf, PNG, contextKeysToRender...)
+}
+
+// RenderPathThroughGraphAs behaves like RenderPathThroughGraph but renders the graph in the given Format
+func RenderPathThroughGraphAs(context *graphflow.ExecutionContext, gf *graphflow.Graphflow, format Format, contextKeysToRender ...string) (bytes.Buffer, error) {
+	if gf.Result() == nil {
+		var buf bytes.Buffer
+		return buf, errors.New("Graphflow hasn't been run, so there's no path to render")
+	}
+	opts := RenderOptions{Format: format}
+	return generateGraph(gf, tracedPath(gf.Result()), contextDescription(context.Snapshot(), contextKeysToRender...), opts)
+}
+
+func generateGraph(gf *graphflow.Graphflow, path *pathModel, description string, opts RenderOptions) (bytes.Buffer, error) {
+	var buf bytes.Buffer
+	m := newGraphModel(gf, path, description, opts)
+	format := opts.Format
+	if format == "" {
+		format = PNG
+	}
+	switch format {
+	case DOT:
+		err := writeDOT(&buf, m)
+		return buf, err
+	case PNG, JPEG, SVG, PDF:
+	default:
+		return buf, fmt.Errorf("Rendering format \"%s\" isn't supported", format)
+	}
+	g := graphviz.New()
+	parentGraph, err := g.Graph()
+	if err != nil {
+		return buf, err
+	}
+	defer func() {
+		if err := parentGraph.Close(); err != nil {
+			log.Fatal(err)
+		}
+		g.Close()
+	}()
+	if m.rankDir != "" {
+		parentGraph.SetRankDir(cgraph.RankDir(m.rankDir))
+	}
+	if m.dpi > 0 {
+		parentGraph.SetDPI(m.dpi)
+	}
+	if m.theme.Background != "" {
+		parentGraph.SetBackgroundColor(m.theme.Background)
+	}
+	if m.theme.Font != "" {
+		parentGraph.SetFontColor(m.theme.Font)
+	}
+	if m.fontName != "" {
+		parentGraph.SafeSet("fontname", m.fontName, "Times-Roman")
+	}
+	// for each task group, create a sub-graph
+	graphs := []*cgraph.Graph{}
+	for i, c := range m.clusters {
+		// TaskGroups come after the TaskGroup they're nested inside
+		parent := parentGraph
+		if c.parent >= 0 {
+			parent = graphs[c.parent]
+		}
+		// clusters are named by index, as TaskGroups in different places can share a name
+		graph := parent.SubGraph(fmt.Sprintf("cluster_%d", i), 1)
+		graph.SetLabel(c.name)
+		graph.SetLabelJust("l")
+		graph.SetStyle("filled")
+		if bgColor := c.bgColor(m.theme); bgColor != "" {
+			graph.SetBackgroundColor(bgColor)
+		}
+		if c.description != "" {
+			graph.SafeSet("tooltip", c.description, "")
+		}
+		graphs = append(graphs, graph)
+	}
+	nodes := make(map[string]*cgraph.Node)
+	for _, nm := range m.nodes {
+		graph := parentGraph
+		if nm.cluster >= 0 {
+			graph = graphs[nm.cluster]
+		}
+		n, err := graph.CreateNode(nm.id)
+		if err != nil {
+			return buf, err
+		}
+		n.SetID(nodeID(nm.id))
+		n.SetLabel(nm.fullLabel())
+		if nm.tooltip != "" {
+			n.SetTooltip(nm.tooltip)
+		}
+		if nm.url != "" {
+			n.SetURL(nm.url)
+		}
+		n.SetStyle("filled")
+		if nm.colorScheme != "" {
+			n.SetColorScheme(nm.colorScheme)
+		}
+		n.SetColor(nm.color)
+		n.SetFontColor(nm.fontColor)
+		if nm.shape != "" {
+			n.SetShape(cgraph.Shape(nm.shape))
+		}
+		if m.fontName != "" {
+			n.SafeSet("fontname", m.fontName, "Times-Roman")
+		}
+		nodes[nm.id] = n
+	}
+	if m.description != "" {
+		n, err := parentGraph.CreateNode(m.description)
 		if err != nil {
-			var buf bytes.Buffer
 			return buf, err
 		}
+		n.SetShape(cgraph.UnderlineShape)
+		n.SetMargin(0.2)
+		if m.theme.Font != "" {
+			n.SetFontColor(m.theme.Font)
+		}
+		if m.fontName != "" {
+			n.SafeSet("fontname", m.fontName, "Times-Roman")
+		}
+	}
+	for _, em := range m.edges {
+		e, err := parentGraph.CreateEdge("to", nodes[em.from], nodes[em.to])
+		if err != nil {
+			return buf, err
+		}
+		e.SetID(em.id())
+		if label := em.fullLabel(); label != "" {
+			e.SetLabel(label)
+		}
+		if em.taken {
+			e.SetPenWidth(2)
+		}
+		if em.dashed {
+			e.SetStyle(cgraph.DashedEdgeStyle)
+		}
+		if m.theme.Edge != "" {
+			e.SetColor(m.theme.Edge)
+		}
+		if m.theme.Font != "" {
+			e.SetFontColor(m.theme.Font)
+		}
+		if m.fontName != "" {
+			e.SafeSet("fontname", m.fontName, "Times-Roman")
+		}
 	}
-	return generateGraph(gf, true, contextKeysToRender...)
+	if format == PDF {
+		// Graphviz's json output holds the layout as drawing operations, which are written out as a PDF
+		var layout bytes.Buffer
+		if err := g.Render(parentGraph, graphviz.Format("json"), &layout); err != nil {
+			return buf, err
+		}
+		err := writePDF(&buf, layout.Bytes())
+		return buf, err
+	}
+	if err := g.Render(parentGraph, graphviz.Format(format), &buf); err != nil {
+		return buf, err
+	}
+	return buf, nil
 }
 
-func generateGraph(gf *graphflow.Graphflow, showPath bool, contextKeysToRender ...string) (bytes.Buffer, error) {
+// RenderDiff returns a buffer of bytes containing a graphviz png representation of the union of two versions of a
+// graphflow, as compared by graphflow.Diff. Tasks and Paths that have been added are drawn in green, those that have
+// been removed in red, and everything that's unchanged in grey.
+func RenderDiff(old *graphflow.Graphflow, updated *graphflow.Graphflow) (bytes.Buffer, error) {
 	var buf bytes.Buffer
+	d := graphflow.Diff(old, updated)
 	g := graphviz.New()
 	parentGraph, err := g.Graph()
 	if err != nil {
@@ -42,101 +243,113 @@ func generateGraph(gf *graphflow.Graphflow, showPath bool, contextKeysToRender .
 		}
 		g.Close()
 	}()
+	added := make(map[string]bool)
+	for _, id := range d.AddedTasks {
+		added[id] = true
+	}
+	removed := make(map[string]bool)
+	for _, id := range d.RemovedTasks {
+		removed[id] = true
+	}
+	// the union of both versions' Tasks, with removed Tasks taken from the old version
+	tasks := []graphflow.TaskIntf{}
+	versions := make(map[graphflow.TaskIntf]*graphflow.Graphflow)
+	for _, t := range updated.Tasks() {
+		tasks = append(tasks, t)
+		versions[t] = updated
+	}
+	for _, t := range old.Tasks() {
+		if removed[old.TaskID(t)] {
+			tasks = append(tasks, t)
+			versions[t] = old
+		}
+	}
+	// TaskGroups are matched by name and the names of the TaskGroups they're nested in, with removed Tasks shown in
+	// their old TaskGroup
 	graphs := make(map[graphflow.TaskIntf]*cgraph.Graph)
-	// first of all link each task to the parent graph
-	for _, t := range gf.Tasks() {
+	for _, t := range tasks {
 		graphs[t] = parentGraph
 	}
-	// for each task group, create a sub-graph
-	for _, tg := range gf.TaskGroups() {
-		graph := parentGraph.SubGraph(fmt.Sprintf("cluster_%s", tg.Name()), 1)
-		graph.SetLabel(tg.Name())
-		graph.SetLabelJust("l")
-		graph.SetStyle("filled")
-		graph.SetBackgroundColor("lightgrey")
-		for _, t := range tg.Tasks() {
-			// link tasks to the subgraph instead
-			graphs[t] = graph
+	clusters := make(map[string]*cgraph.Graph)
+	for _, gf := range []*graphflow.Graphflow{updated, old} {
+		for _, tg := range gf.TaskGroups() {
+			graph, exists := clusters[tg.QualifiedName()]
+			if !exists {
+				parent := parentGraph
+				if tg.Parent() != nil {
+					parent = clusters[tg.Parent().QualifiedName()]
+				}
+				graph = parent.SubGraph(fmt.Sprintf("cluster_%d", len(clusters)), 1)
+				graph.SetLabel(tg.Name())
+				graph.SetLabelJust("l")
+				graph.SetStyle("filled")
+				graph.SetBackgroundColor("lightgrey")
+				clusters[tg.QualifiedName()] = graph
+			}
+			for _, t := range tg.Tasks() {
+				if versions[t] == gf {
+					graphs[t] = graph
+				}
+			}
 		}
 	}
-
-	nodes := make(map[graphflow.TaskIntf]*cgraph.Node)
-	for _, t := range gf.Tasks() {
-		n, err := graphs[t].CreateNode(fmt.Sprintf("%p", t))
-		n.SetLabel(t.String())
+	nodes := make(map[string]*cgraph.Node)
+	for _, t := range tasks {
+		id := versions[t].TaskID(t)
+		n, err := graphs[t].CreateNode(id)
 		if err != nil {
 			return buf, err
 		}
+		n.SetLabel(t.String())
 		n.SetStyle("filled")
-		if showPath {
-			n.SetColorScheme("greys3")
-			n.SetColor("1")
-			n.SetFontColor("2")
-		} else {
+		switch {
+		case added[id]:
+			n.SetColorScheme("paired10")
+			n.SetColor("4") // green
+		case removed[id]:
 			n.SetColorScheme("paired10")
 			n.SetColor("6") // red
+		default:
+			n.SetColorScheme("greys3")
+			n.SetColor("1") // grey
 		}
-		nodes[t] = n
+		nodes[id] = n
 	}
-	if showPath {
-		desc := ""
-		for _, k := range contextKeysToRender {
-			desc = fmt.Sprintf("%s\n%s = %v", desc, k, gf.GetContext().Get(k))
-		}
-		if desc != "" {
-			desc = fmt.Sprintf("This is the path taken when:\n%s", desc)
-			n, err := parentGraph.CreateNode(desc)
-			if err != nil {
-				return buf, err
-			}
-			n.SetShape(cgraph.UnderlineShape)
-			n.SetMargin(0.2)
+	addedPaths := append(d.AddedPaths, d.RetargetedPaths...)
+	removedPaths := d.RemovedPaths
+	for _, p := range d.RetargetedPaths {
+		removedPaths = append(removedPaths, graphflow.PathChange{From: p.From, Condition: p.Condition, To: p.PreviousTo})
+	}
+	changed := make(map[graphflow.PathChange]bool)
+	for _, p := range addedPaths {
+		changed[graphflow.PathChange{From: p.From, Condition: p.Condition, To: p.To}] = true
+	}
+	edges := []graphflow.PathChange{}
+	for _, path := range updated.OrderedPaths() {
+		p := graphflow.PathChange{From: updated.TaskID(path.From), Condition: path.Condition, To: updated.TaskID(path.To)}
+		if !changed[p] {
+			edges = append(edges, p)
 		}
 	}
-	for from, edge := range gf.Paths() {
-		n1 := nodes[from]
-		for label, to := range edge {
-			n2 := nodes[to]
-			e, err := parentGraph.CreateEdge("to", n1, n2)
+	for _, paths := range [][]graphflow.PathChange{edges, addedPaths, removedPaths} {
+		for _, p := range paths {
+			e, err := parentGraph.CreateEdge("to", nodes[p.From], nodes[p.To])
 			if err != nil {
 				return buf, err
 			}
-			if label != graphflow.ALWAYS {
-				e.SetLabel(graphflow.PathConditionName[label])
+			if p.Condition != graphflow.ALWAYS {
+				e.SetLabel(graphflow.PathConditionName[p.Condition])
 			}
-			_, isEndTask := to.(*graphflow.EndTask)
-			if isEndTask {
-				n2.SetColorScheme("paired10")
-				n2.SetColor("7") // orange
-				n2.SetFontColor("")
-			}
-			if showPath {
-				if !gf.Executed()[to] {
-					n2.SetColorScheme("greys3")
-					n2.SetColor("1") // grey
-					n2.SetFontColor("2")
-				}
-			}
-		}
-		n1.SetColorScheme("paired10")
-		n1.SetFontColor("")
-		_, isStartTask := from.(*graphflow.StartTask)
-		if isStartTask {
-			n1.SetColor("7") // orange
-		} else {
-			n1.SetColor("9") // mauve
-			for label := range edge {
-				if label == graphflow.YES || label == graphflow.NO {
-					n1.SetColor("3") // green
-					break
-				}
-			}
-		}
-		if showPath {
-			if !gf.Executed()[from] {
-				n1.SetColorScheme("greys3")
-				n1.SetColor("1") // grey
-				n1.SetFontColor("2")
+			switch {
+			case changed[graphflow.PathChange{From: p.From, Condition: p.Condition, To: p.To}]:
+				e.SetColor("green")
+				e.SetFontColor("green")
+			case contains(removedPaths, p):
+				e.SetColor("red")
+				e.SetFontColor("red")
+				e.SetStyle(cgraph.DashedEdgeStyle)
+			default:
+				e.SetColor("grey")
 			}
 		}
 	}
@@ -145,3 +358,12 @@ func generateGraph(gf *graphflow.Graphflow, showPath bool, contextKeysToRender .
 	}
 	return buf, nil
 }
+
+func contains(paths []graphflow.PathChange, path graphflow.PathChange) bool {
+	for _, p := range paths {
+		if p == path {
+			return true
+		}
+	}
+	return false
+}
@@ -104,6 +104,7 @@ func TestGraphflowRenderPathThroughGraph(t *testing.T) {
 	ctx.Set("Sky", "Clear")
 	ctx.Set("Forecast", "")
 	gf := buildGraphflow()
+	assert.Nil(t, gf.Run(ctx))
 
 	bytes, err := RenderPathThroughGraph(ctx, gf, "Sky")
 
@@ -113,6 +114,19 @@ func TestGraphflowRenderPathThroughGraph(t *testing.T) {
 	assert.Equal(t, "Sun", ctx.Get("Forecast"))
 }
 
+func TestRenderPathThroughGraphDoesNotRunTasks(t *testing.T) {
+	ctx := new(graphflow.ExecutionContext)
+	ctx.Set("Sky", "Clear")
+	ctx.Set("Forecast", "")
+	gf := buildGraphflow()
+
+	_, err := RenderPathThroughGraph(ctx, gf, "Sky")
+
+	assert.EqualError(t, err, "Graphflow hasn't been run, so there's no path to render")
+	assert.Equal(t, "", ctx.Get("Forecast"))
+	assert.Empty(t, gf.Executed())
+}
+
 func TestWorkflowWithNoPathsShouldRenderFine(t *testing.T) {
 	var gf graphflow.Graphflow
 
@@ -144,7 +158,10 @@ func TestWorkflowWithNoPathsShouldRenderPathThroughGraphFine(t *testing.T) {
 	gf.AddTask(forecastSun)
 	gf.AddTask(end)
 
-	_, err := RenderPathThroughGraph(new(graphflow.ExecutionContext), &gf)
+	ctx := new(graphflow.ExecutionContext)
+	assert.Nil(t, gf.Run(ctx))
+
+	_, err := RenderPathThroughGraph(ctx, &gf)
 
 	assert.Nil(t, err)
 }
@@ -166,11 +183,12 @@ func TestWorkflowWithTaskWithNoName(t *testing.T) {
 	gf.AddPath(start, graphflow.ALWAYS, forecastNothing)
 	gf.AddPath(forecastNothing, graphflow.ALWAYS, end)
 
-	_, err := RenderPathThroughGraph(new(graphflow.ExecutionContext), &gf)
+	ctx := new(graphflow.ExecutionContext)
+	assert.Nil(t, gf.Run(ctx))
 
-	assert.Nil(t, err)
+	_, err := RenderPathThroughGraph(ctx, &gf)
 
-	ctx := gf.GetContext()
+	assert.Nil(t, err)
 	assert.Equal(t, "Nothing", ctx.Get("Forecast"))
 }
 
@@ -195,7 +213,10 @@ func TestAddTaskGroups(t *testing.T) {
 	assert.Contains(t, gf.TaskGroups(), taskGroup)
 	assert.Contains(t, gf.TaskGroups()[0].Tasks(), forecastSun)
 
-	_, err := RenderPathThroughGraph(new(graphflow.ExecutionContext), &gf)
+	ctx := new(graphflow.ExecutionContext)
+	assert.Nil(t, gf.Run(ctx))
+
+	_, err := RenderPathThroughGraph(ctx, &gf)
 	assert.Nil(t, err)
 }
 
@@ -219,7 +240,55 @@ func TestTaskGroupsWithDuplicateTasks(t *testing.T) {
 	duplicateTaskGroup := gf.NewTaskGroup("my other taskgroup")
 	duplicateTaskGroup.AddTasks(forecastSun)
 
-	_, err := RenderPathThroughGraph(new(graphflow.ExecutionContext), &gf)
+	err := gf.Run(new(graphflow.ExecutionContext))
 
 	assert.NotNil(t, err)
 }
+
+func TestRenderGraphWithOutcomes(t *testing.T) {
+	var gf graphflow.Graphflow
+
+	// create task instances
+	start := new(graphflow.StartTask)
+	isTheSkyCloudy := new(IsTheSkyCloudy)
+	takeUmbrella := graphflow.NewEndTask("Take Umbrella")
+	wearSunglasses := graphflow.NewEndTask("Wear Sunglasses")
+
+	// add task instances to the graphflow
+	gf.AddTask(start)
+	gf.AddTask(isTheSkyCloudy)
+	gf.AddTask(takeUmbrella)
+	gf.AddTask(wearSunglasses)
+
+	// add task paths to the graphflow
+	gf.AddPath(start, graphflow.ALWAYS, isTheSkyCloudy)
+	gf.AddPath(isTheSkyCloudy, graphflow.YES, takeUmbrella)
+	gf.AddPath(isTheSkyCloudy, graphflow.NO, wearSunglasses)
+
+	bytes, err := RenderGraph(&gf)
+
+	assert.NotEmpty(t, bytes.Bytes())
+	assert.Nil(t, err)
+
+	color := outcomeColor(&gf, takeUmbrella, DefaultTheme)
+	assert.Equal(t, "pastel28", color.Scheme)
+	assert.Equal(t, "1", color.Fill)
+	color = outcomeColor(&gf, wearSunglasses, DefaultTheme)
+	assert.Equal(t, "2", color.Fill)
+}
+
+func TestRenderDiff(t *testing.T) {
+	old := buildGraphflow()
+	old.NewTaskGroup("Forecasting").AddTasks(old.Task("forecast-rain"), old.Task("forecast-sun"))
+
+	updated := buildGraphflow()
+	forecastNothing := updated.AddTask(new(TaskWithNoName))
+	updated.RemoveTask(updated.Task("forecast-rain"))
+	updated.AddPath(updated.Task("is-the-sky-cloudy"), graphflow.YES, forecastNothing)
+	updated.AddPath(forecastNothing, graphflow.ALWAYS, updated.Task("end"))
+
+	bytes, err := RenderDiff(old, updated)
+
+	assert.NotEmpty(t, bytes.Bytes())
+	assert.Nil(t, err)
+}
@@ -0,0 +1,243 @@
+package rendering
+
+import (
+	"fmt"
+	"html/template"
+	"io"
+	"strings"
+
+	"github.com/futrli/graphflow"
+)
+
+// htmlData is the data embedded in the page written by WriteHTML, for its script to read
+type htmlData struct {
+	Tasks map[string]htmlTask `json:"tasks"`
+	Runs  []htmlRun           `json:"runs"`
+}
+
+type htmlTask struct {
+	Label       string   `json:"label"`
+	Description string   `json:"description,omitempty"`
+	Owner       string   `json:"owner,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
+	DocURL      string   `json:"docURL,omitempty"`
+	SLA         string   `json:"sla,omitempty"`
+	Inputs      []string `json:"inputs,omitempty"`
+	Outputs     []string `json:"outputs,omitempty"`
+}
+
+type htmlRun struct {
+	Name  string     `json:"name"`
+	Steps []htmlStep `json:"steps"`
+}
+
+type htmlStep struct {
+	Task string `json:"task"`
+	// Node is the SVG ID of the Task's node
+	Node string `json:"node"`
+	// Edge is the SVG ID of the Path followed to the next Step, if there is one
+	Edge    string            `json:"edge,omitempty"`
+	Context map[string]string `json:"context,omitempty"`
+}
+
+// WriteHTML writes a single page of HTML that can be viewed offline, containing an SVG of the graphflow. Hovering
+// over a Task shows its Metadata and the ExecutionContext keys it reads and writes, if it implements
+// graphflow.IODeclarer. Any RunResults given can be picked from a list and replayed a step
+// at a time, showing the ExecutionContext after each step if the graphflow was set to RecordContext.
+func WriteHTML(w io.Writer, gf *graphflow.Graphflow, runs ...*graphflow.RunResult) error {
+	svg, err := RenderGraphAs(gf, SVG)
+	if err != nil {
+		return err
+	}
+	data := htmlData{Tasks: make(map[string]htmlTask), Runs: []htmlRun{}}
+	for _, t := range gf.Tasks() {
+		metadata := gf.Metadata(t)
+		task := htmlTask{
+			Label:       t.String(),
+			Description: metadata.Description,
+			Owner:       metadata.Owner,
+			Tags:        metadata.Tags,
+			DocURL:      metadata.DocURL,
+		}
+		if metadata.SLA > 0 {
+			task.SLA = metadata.SLA.String()
+		}
+		if declarer, ok := t.(graphflow.IODeclarer); ok {
+			task.Inputs = declarer.Inputs()
+			task.Outputs = declarer.Outputs()
+		}
+		data.Tasks[gf.TaskID(t)] = task
+	}
+	for i, result := range runs {
+		run := htmlRun{Name: htmlRunName(i, result), Steps: []htmlStep{}}
+		for j, step := range result.Steps {
+			s := htmlStep{Task: step.TaskID, Node: nodeID(step.TaskID)}
+			if j < len(result.Steps)-1 {
+				s.Edge = edgeID(step.TaskID, step.ExitPath)
+			}
+			if step.Context != nil {
+				s.Context = make(map[string]string)
+				for k, v := range step.Context {
+					s.Context[k] = fmt.Sprintf("%v", v)
+				}
+			}
+			run.Steps = append(run.Steps, s)
+		}
+		data.Runs = append(data.Runs, run)
+	}
+	// drop the XML declaration and doctype so the SVG can be embedded in the page
+	image := svg.String()
+	if i := strings.Index(image, "<svg"); i >= 0 {
+		image = image[i:]
+	}
+	return htmlTemplate.Execute(w, struct {
+		SVG  template.HTML
+		Data htmlData
+	}{
+		SVG:  template.HTML(image),
+		Data: data,
+	})
+}
+
+// htmlRunName names a run in the list of runs to replay
+func htmlRunName(i int, result *graphflow.RunResult) string {
+	name := fmt.Sprintf("Run %d", i+1)
+	switch {
+	case result.Ended && result.Outcome != "":
+		return fmt.Sprintf("%s: %s", name, result.Outcome)
+	case result.Ended:
+		return fmt.Sprintf("%s: ended", name)
+	case result.Next != "":
+		return fmt.Sprintf("%s: failed at %s", name, result.Next)
+	}
+	return name
+}
+
+var htmlTemplate = template.Must(template.New("graphflow").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>Graphflow</title>
+<style>
+body { font-family: sans-serif; margin: 0; display: flex; }
+#graph { flex: 1; padding: 1em; overflow: auto; }
+#sidebar { width: 22em; padding: 1em; border-left: 1px solid #ccc; background: #fafafa; min-height: 100vh; }
+#sidebar h2 { font-size: 1.1em; }
+#sidebar table { border-collapse: collapse; width: 100%; }
+#sidebar td { border-top: 1px solid #ddd; padding: 0.2em 0.4em; vertical-align: top; font-family: monospace; }
+.hint { color: #777; }
+g.node { cursor: pointer; }
+g.node.visited ellipse, g.node.visited polygon { stroke: #33a02c; stroke-width: 3; }
+g.node.current ellipse, g.node.current polygon { stroke: #e31a1c; stroke-width: 4; }
+g.edge.taken path, g.edge.taken polygon { stroke: #33a02c; stroke-width: 3; }
+</style>
+</head>
+<body>
+<div id="graph">{{.SVG}}</div>
+<div id="sidebar">
+<h2>Task</h2>
+<div id="task"><p class="hint">Hover over a Task to see what it does.</p></div>
+<h2>Runs</h2>
+<div id="runs">
+<select id="run"></select>
+<p><button id="previous">&#9664; Previous</button> <button id="next">Next &#9654;</button> <span id="position"></span></p>
+<div id="context"></div>
+</div>
+</div>
+<script>
+var data = {{.Data}};
+var runIndex = 0, stepIndex = 0;
+
+function element(tag, text, className) {
+	var e = document.createElement(tag);
+	if (text !== undefined) { e.textContent = text; }
+	if (className) { e.className = className; }
+	return e;
+}
+
+function showTask(id) {
+	var task = data.tasks[id], panel = document.getElementById("task");
+	if (!task) { return; }
+	panel.innerHTML = "";
+	panel.appendChild(element("h3", task.label));
+	panel.appendChild(element("p", task.description || "No description.", task.description ? "" : "hint"));
+	[["Owner", task.owner], ["Tags", task.tags && task.tags.join(", ")], ["SLA", task.sla]].forEach(function (field) {
+		if (!field[1]) { return; }
+		var p = element("p");
+		p.appendChild(element("strong", field[0] + ": "));
+		p.appendChild(document.createTextNode(field[1]));
+		panel.appendChild(p);
+	});
+	if (task.docURL) {
+		var link = element("a", "Documentation");
+		link.href = task.docURL;
+		panel.appendChild(element("p")).appendChild(link);
+	}
+	[["Inputs", task.inputs], ["Outputs", task.outputs]].forEach(function (io) {
+		if (!io[1] || io[1].length === 0) { return; }
+		panel.appendChild(element("strong", io[0]));
+		var list = element("ul");
+		io[1].forEach(function (key) { list.appendChild(element("li", key)); });
+		panel.appendChild(list);
+	});
+}
+
+function showStep() {
+	document.querySelectorAll(".visited, .current, .taken").forEach(function (e) {
+		e.classList.remove("visited", "current", "taken");
+	});
+	var context = document.getElementById("context"), position = document.getElementById("position");
+	context.innerHTML = "";
+	var run = data.runs[runIndex];
+	if (!run) {
+		position.textContent = "";
+		context.appendChild(element("p", "No runs were included.", "hint"));
+		return;
+	}
+	position.textContent = "Step " + (stepIndex + 1) + " of " + run.steps.length;
+	run.steps.slice(0, stepIndex + 1).forEach(function (step, i) {
+		var node = document.getElementById(step.node);
+		if (node) { node.classList.add(i === stepIndex ? "current" : "visited"); }
+		var edge = i < stepIndex && step.edge ? document.getElementById(step.edge) : null;
+		if (edge) { edge.classList.add("taken"); }
+	});
+	var step = run.steps[stepIndex];
+	if (!step) { return; }
+	showTask(step.task);
+	if (!step.context) {
+		context.appendChild(element("p", "The ExecutionContext wasn't recorded for this run.", "hint"));
+		return;
+	}
+	var table = element("table");
+	Object.keys(step.context).forEach(function (key) {
+		var row = element("tr");
+		row.appendChild(element("td", key));
+		row.appendChild(element("td", step.context[key]));
+		table.appendChild(row);
+	});
+	context.appendChild(table);
+}
+
+document.querySelectorAll("g.node").forEach(function (node) {
+	// node IDs are Task IDs prefixed by "task-"
+	node.addEventListener("mouseenter", function () { showTask(node.id.slice(5)); });
+});
+var select = document.getElementById("run");
+data.runs.forEach(function (run, i) {
+	var option = element("option", run.name);
+	option.value = i;
+	select.appendChild(option);
+});
+select.addEventListener("change", function () { runIndex = Number(select.value); stepIndex = 0; showStep(); });
+document.getElementById("previous").addEventListener("click", function () {
+	if (stepIndex > 0) { stepIndex--; showStep(); }
+});
+document.getElementById("next").addEventListener("click", function () {
+	var run = data.runs[runIndex];
+	if (run && stepIndex < run.steps.length - 1) { stepIndex++; showStep(); }
+});
+showStep();
+</script>
+</body>
+</html>
+`))
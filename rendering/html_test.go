@@ -0,0 +1,96 @@
+package rendering
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/futrli/graphflow"
+	"github.com/stretchr/testify/assert"
+)
+
+// DescribedForecast is a Task struct that describes itself and declares its inputs and outputs
+type DescribedForecast struct {
+	graphflow.Task
+}
+
+// String returns a description of the Task
+func (t *DescribedForecast) String() string {
+	return "Described Forecast"
+}
+
+// Description explains what the Task does
+func (t *DescribedForecast) Description() string {
+	return "Forecasts the weather from the state of the sky"
+}
+
+// Inputs lists the ExecutionContext keys the Task reads
+func (t *DescribedForecast) Inputs() []string {
+	return []string{"Sky"}
+}
+
+// Outputs lists the ExecutionContext keys the Task writes
+func (t *DescribedForecast) Outputs() []string {
+	return []string{"Forecast"}
+}
+
+// Execute sets the ExecutionContext's Forecast value from its Sky value
+func (t *DescribedForecast) Execute(ctx *graphflow.ExecutionContext) error {
+	ctx.Set("Forecast", ctx.Get("Sky"))
+	return nil
+}
+
+func TestWriteHTML(t *testing.T) {
+	gf := new(graphflow.Graphflow)
+	start := gf.AddTask(new(graphflow.StartTask))
+	forecast := gf.AddTask(new(DescribedForecast))
+	end := gf.AddTask(new(graphflow.EndTask))
+	gf.AddPath(start, graphflow.ALWAYS, forecast)
+	gf.AddPath(forecast, graphflow.ALWAYS, end)
+	gf.SetMetadata(end, graphflow.Metadata{Owner: "Weather Team", DocURL: "https://example.com/end"})
+	gf.RecordContext(true)
+	ctx := new(graphflow.ExecutionContext)
+	ctx.Set("Sky", "Cloudy")
+	assert.Nil(t, gf.Run(ctx))
+
+	var buf bytes.Buffer
+	err := WriteHTML(&buf, gf, gf.Result())
+
+	assert.Nil(t, err)
+	html := buf.String()
+	assert.Contains(t, html, "<!DOCTYPE html>")
+	assert.Contains(t, html, "<svg")
+	assert.NotContains(t, html, "<?xml")
+	assert.Contains(t, html, `"description":"Forecasts the weather from the state of the sky"`)
+	assert.Contains(t, html, `"inputs":["Sky"],"outputs":["Forecast"]`)
+	assert.Contains(t, html, `"end":{"label":"End","owner":"Weather Team","docURL":"https://example.com/end"}`)
+	assert.Contains(t, html, `"name":"Run 1: ended"`)
+	assert.Contains(t, html, `{"task":"described-forecast","node":"task-described-forecast","edge":"path-described-forecast:ALWAYS","context":{"Forecast":"Cloudy","Sky":"Cloudy"}}`)
+	assert.Contains(t, html, `id="path&#45;described&#45;forecast:ALWAYS"`)
+}
+
+func TestWriteHTMLTaskIDsDontClashWithThePage(t *testing.T) {
+	gf := new(graphflow.Graphflow)
+	start := gf.AddTask(new(graphflow.StartTask))
+	graph := gf.AddTask(graphflow.ActionFunc("Graph", func(ctx *graphflow.ExecutionContext) error { return nil }))
+	end := gf.AddTask(new(graphflow.EndTask))
+	gf.AddPath(start, graphflow.ALWAYS, graph)
+	gf.AddPath(graph, graphflow.ALWAYS, end)
+
+	var buf bytes.Buffer
+	err := WriteHTML(&buf, gf)
+
+	assert.Nil(t, err)
+	html := buf.String()
+	assert.Equal(t, "graph", gf.TaskID(graph))
+	assert.Equal(t, 1, strings.Count(html, `id="graph"`))
+	assert.Contains(t, html, `<g id="task&#45;graph" class="node">`)
+}
+
+func TestWriteHTMLWithoutRuns(t *testing.T) {
+	var buf bytes.Buffer
+	err := WriteHTML(&buf, buildGraphflow())
+
+	assert.Nil(t, err)
+	assert.Contains(t, buf.String(), `"runs":[]`)
+}
@@ -0,0 +1,176 @@
+package rendering
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"unicode"
+
+	"github.com/futrli/graphflow"
+)
+
+// MarkdownOptions configures the documentation written by WriteMarkdown
+type MarkdownOptions struct {
+	// Title is the top level heading, defaulting to "Graphflow"
+	Title string
+}
+
+// WriteMarkdown writes Markdown documentation of the graphflow, so a readable spec can be generated from the code
+// that builds it. It starts with a Mermaid diagram, followed by a section for each Task in the order they're reached
+// from the StartTask, describing its kind, TaskGroup, Metadata, the ExecutionContext keys it reads and writes if it
+// implements graphflow.IODeclarer, and where each of its Paths leads. A section for each TaskGroup follows.
+func WriteMarkdown(w io.Writer, gf *graphflow.Graphflow, opts MarkdownOptions) error {
+	title := opts.Title
+	if title == "" {
+		title = "Graphflow"
+	}
+	m := newGraphModel(gf, nil, "", RenderOptions{})
+	order := textOrder(gf, m)
+	anchors, groupAnchors := markdownAnchors(gf, order)
+	labels := make(map[string]string)
+	for _, n := range order {
+		labels[n.id] = n.label
+	}
+	groups := make(map[graphflow.TaskIntf]*graphflow.TaskGroup)
+	for _, tg := range gf.TaskGroups() {
+		for _, t := range tg.Tasks() {
+			groups[t] = tg
+		}
+	}
+
+	bw := bufio.NewWriter(w)
+	fmt.Fprintf(bw, "# %s\n\n", title)
+	if gf.Version() != "" {
+		fmt.Fprintf(bw, "Version `%s`, hash `%s`\n\n", gf.Version(), gf.Hash())
+	} else {
+		fmt.Fprintf(bw, "Hash `%s`\n\n", gf.Hash())
+	}
+	fmt.Fprintln(bw, "```mermaid")
+	if err := writeMermaid(bw, gf, m); err != nil {
+		return err
+	}
+	fmt.Fprint(bw, "```\n\n## Tasks\n")
+	for _, n := range order {
+		t := gf.Task(n.id)
+		metadata := gf.Metadata(t)
+		fmt.Fprintf(bw, "\n### %s\n\n", n.label)
+		if metadata.Description != "" {
+			fmt.Fprintf(bw, "%s\n\n", metadata.Description)
+		}
+		fmt.Fprintf(bw, "- ID: `%s`\n", n.id)
+		fmt.Fprintf(bw, "- Kind: %s\n", TaskKindName[n.kind])
+		if tg, grouped := groups[t]; grouped {
+			fmt.Fprintf(bw, "- Group: [%s](#%s)\n", tg.QualifiedName(), groupAnchors[tg])
+		}
+		if metadata.Owner != "" {
+			fmt.Fprintf(bw, "- Owner: %s\n", metadata.Owner)
+		}
+		if len(metadata.Tags) > 0 {
+			fmt.Fprintf(bw, "- Tags: %s\n", markdownCodes(metadata.Tags))
+		}
+		if metadata.SLA > 0 {
+			fmt.Fprintf(bw, "- SLA: %s\n", metadata.SLA)
+		}
+		if metadata.DocURL != "" {
+			fmt.Fprintf(bw, "- Documentation: <%s>\n", metadata.DocURL)
+		}
+		if declarer, ok := t.(graphflow.IODeclarer); ok {
+			if len(declarer.Inputs()) > 0 {
+				fmt.Fprintf(bw, "- Inputs: %s\n", markdownCodes(declarer.Inputs()))
+			}
+			if len(declarer.Outputs()) > 0 {
+				fmt.Fprintf(bw, "- Outputs: %s\n", markdownCodes(declarer.Outputs()))
+			}
+		}
+		paths := []*edgeModel{}
+		for _, e := range m.edges {
+			if e.from == n.id {
+				paths = append(paths, e)
+			}
+		}
+		if len(paths) > 0 {
+			fmt.Fprintln(bw, "- Paths:")
+		}
+		for _, e := range paths {
+			fmt.Fprintf(bw, "  - %s: [%s](#%s)\n", graphflow.PathConditionName[e.condition], labels[e.to], anchors[e.to])
+		}
+	}
+	if len(gf.TaskGroups()) > 0 {
+		fmt.Fprint(bw, "\n## Groups\n")
+	}
+	for _, tg := range gf.TaskGroups() {
+		fmt.Fprintf(bw, "\n### %s\n\n", tg.Name())
+		if tg.Description() != "" {
+			fmt.Fprintf(bw, "%s\n\n", tg.Description())
+		}
+		if tg.Parent() != nil {
+			fmt.Fprintf(bw, "- Inside: [%s](#%s)\n", tg.Parent().Name(), groupAnchors[tg.Parent()])
+		}
+		tasks := []string{}
+		for _, t := range tg.Tasks() {
+			tasks = append(tasks, fmt.Sprintf("[%s](#%s)", t.String(), anchors[gf.TaskID(t)]))
+		}
+		if len(tasks) > 0 {
+			fmt.Fprintf(bw, "- Tasks: %s\n", strings.Join(tasks, ", "))
+		}
+		if handler := tg.ErrorHandler(); handler != nil {
+			fmt.Fprintf(bw, "- Errors handled by: [%s](#%s)\n", handler.String(), anchors[gf.TaskID(handler)])
+		}
+		if policy := tg.Policy(); policy.Retries > 0 {
+			fmt.Fprintf(bw, "- Retries: %d, %s apart\n", policy.Retries, policy.RetryDelay)
+		}
+		if policy := tg.Policy(); policy.Timeout > 0 {
+			fmt.Fprintf(bw, "- Timeout: %s\n", policy.Timeout)
+		}
+	}
+	return bw.Flush()
+}
+
+// markdownAnchors returns the anchors of the headings written for each Task, by its ID, and each TaskGroup, numbering
+// repeated headings the way Git hosts do
+func markdownAnchors(gf *graphflow.Graphflow, order []*nodeModel) (map[string]string, map[*graphflow.TaskGroup]string) {
+	used := map[string]int{"tasks": 1, "groups": 1}
+	anchor := func(heading string) string {
+		a := markdownAnchor(heading)
+		count := used[a]
+		used[a]++
+		if count > 0 {
+			return fmt.Sprintf("%s-%d", a, count)
+		}
+		return a
+	}
+	anchors := make(map[string]string)
+	for _, n := range order {
+		anchors[n.id] = anchor(n.label)
+	}
+	groupAnchors := make(map[*graphflow.TaskGroup]string)
+	for _, tg := range gf.TaskGroups() {
+		groupAnchors[tg] = anchor(tg.Name())
+	}
+	return anchors, groupAnchors
+}
+
+// markdownAnchor returns the anchor a Git host gives a heading: lower case, without punctuation and with spaces
+// replaced by dashes
+func markdownAnchor(heading string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(heading) {
+		switch {
+		case r == ' ':
+			sb.WriteRune('-')
+		case r == '-' || r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r):
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// markdownCodes formats a list of values as inline code, separated by commas
+func markdownCodes(values []string) string {
+	codes := []string{}
+	for _, v := range values {
+		codes = append(codes, fmt.Sprintf("`%s`", v))
+	}
+	return strings.Join(codes, ", ")
+}
@@ -0,0 +1,107 @@
+package rendering
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/futrli/graphflow"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteMarkdown(t *testing.T) {
+	gf := buildGraphflow()
+	gf.SetVersion("v2")
+	forecasting := gf.NewTaskGroup("Forecasting")
+	forecasting.SetDescription("Decides what to forecast")
+	forecasting.AddTasks(gf.Task("forecast-sun"))
+	rain := forecasting.NewTaskGroup("Rain")
+	rain.AddTasks(gf.Task("forecast-rain"))
+	rain.SetErrorHandler(gf.Task("end"))
+	rain.SetPolicy(graphflow.Policy{Retries: 2, RetryDelay: time.Second})
+	gf.SetMetadata(gf.Task("forecast-rain"), graphflow.Metadata{
+		Description: "Forecasts rain",
+		Owner:       "Weather Team",
+		Tags:        []string{"rain"},
+		DocURL:      "https://example.com/rain",
+		SLA:         5 * time.Second,
+	})
+
+	var buf bytes.Buffer
+	err := WriteMarkdown(&buf, gf, MarkdownOptions{Title: "Weather"})
+
+	assert.Nil(t, err)
+	md := buf.String()
+	assert.Contains(t, md, "# Weather\n\nVersion `v2`, hash `"+gf.Hash()+"`\n\n```mermaid\nflowchart TD\n")
+	assert.Contains(t, md, "```\n\n## Tasks\n\n### Start\n")
+	assert.Contains(t, md, `### Is the sky cloudy?
+
+- ID: `+"`is-the-sky-cloudy`"+`
+- Kind: Question
+- Paths:
+  - YES: [Forecast Rain](#forecast-rain)
+  - NO: [Forecast Sun](#forecast-sun)
+`)
+	assert.Contains(t, md, `### Forecast Rain
+
+Forecasts rain
+
+- ID: `+"`forecast-rain`"+`
+- Kind: Action
+- Group: [Forecasting / Rain](#rain)
+- Owner: Weather Team
+- Tags: `+"`rain`"+`
+- SLA: 5s
+- Documentation: <https://example.com/rain>
+- Paths:
+  - ALWAYS: [End](#end)
+`)
+	assert.Contains(t, md, `## Groups
+
+### Forecasting
+
+Decides what to forecast
+
+- Tasks: [Forecast Sun](#forecast-sun)
+
+### Rain
+
+- Inside: [Forecasting](#forecasting)
+- Tasks: [Forecast Rain](#forecast-rain)
+- Errors handled by: [End](#end)
+- Retries: 2, 1s apart
+`)
+}
+
+func TestWriteMarkdownDeclaresInputsAndOutputs(t *testing.T) {
+	gf := new(graphflow.Graphflow)
+	start := gf.AddTask(new(graphflow.StartTask))
+	forecast := gf.AddTask(new(DescribedForecast))
+	end := gf.AddTask(new(graphflow.EndTask))
+	gf.AddPath(start, graphflow.ALWAYS, forecast)
+	gf.AddPath(forecast, graphflow.ALWAYS, end)
+
+	var buf bytes.Buffer
+	err := WriteMarkdown(&buf, gf, MarkdownOptions{})
+
+	assert.Nil(t, err)
+	assert.Contains(t, buf.String(), "# Graphflow\n\nHash `")
+	assert.Contains(t, buf.String(), "Forecasts the weather from the state of the sky\n\n")
+	assert.Contains(t, buf.String(), "- Inputs: `Sky`\n- Outputs: `Forecast`\n")
+	assert.NotContains(t, buf.String(), "## Groups")
+}
+
+func TestMarkdownAnchor(t *testing.T) {
+	assert.Equal(t, "is-the-sky-cloudy", markdownAnchor("Is the sky cloudy?"))
+	assert.Equal(t, "start-onboarding", markdownAnchor("Start (onboarding)"))
+}
+
+func TestMarkdownAnchorsNumberRepeatedHeadings(t *testing.T) {
+	gf := buildGraphflow()
+	tg := gf.NewTaskGroup("Forecast Rain")
+
+	anchors, groupAnchors := markdownAnchors(gf, textOrder(gf, newGraphModel(gf, nil, "", RenderOptions{})))
+
+	assert.Equal(t, "forecast-rain", anchors["forecast-rain"])
+	assert.Equal(t, "forecast-rain-1", groupAnchors[tg])
+}
@@ -0,0 +1,136 @@
+package rendering
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/futrli/graphflow"
+)
+
+// colors maps the Graphviz colour schemes used for nodes to their colours, for outputs that need explicit colours
+var colors = map[string][]string{
+	"paired10": {"#a6cee3", "#1f78b4", "#b2df8a", "#33a02c", "#fb9a99", "#e31a1c", "#fdbf6f", "#ff7f00", "#cab2d6", "#6a3d9a"},
+	"pastel28": {"#b3e2cd", "#fdcdac", "#cbd5e8", "#f4cae4", "#e6f5c9", "#fff2ae", "#f1e2cc", "#cccccc"},
+	"greys3":   {"#f0f0f0", "#bdbdbd", "#636363"},
+}
+
+// WriteMermaid writes a Mermaid flowchart of the graphflow, with TaskGroups as subgraphs and the same colours
+// as RenderGraph, for embedding in Markdown that's rendered by a Git host
+func WriteMermaid(w io.Writer, gf *graphflow.Graphflow) error {
+	return writeMermaid(w, gf, newGraphModel(gf, nil, "", RenderOptions{}))
+}
+
+// WriteMermaidPath writes a Mermaid flowchart of the graphflow with the path recorded in a RunResult highlighted.
+// Tasks that weren't executed are greyed out and the Paths followed are drawn thicker.
+func WriteMermaidPath(w io.Writer, gf *graphflow.Graphflow, result *graphflow.RunResult) error {
+	return writeMermaid(w, gf, newGraphModel(gf, tracedPath(result), "", RenderOptions{}))
+}
+
+func writeMermaid(w io.Writer, gf *graphflow.Graphflow, m *graphModel) error {
+	ids := mermaidIDs(m)
+	shapes := make(map[string]string)
+	for _, t := range gf.Tasks() {
+		shapes[gf.TaskID(t)] = "[%s]"
+		switch t.(type) {
+		case *graphflow.StartTask, *graphflow.EndTask:
+			shapes[gf.TaskID(t)] = "([%s])"
+		}
+	}
+	for _, e := range m.edges {
+		if e.condition == graphflow.YES || e.condition == graphflow.NO {
+			shapes[e.from] = "{%s}"
+		}
+	}
+	bw := bufio.NewWriter(w)
+	fmt.Fprintln(bw, "flowchart TD")
+	writeNode := func(indent string, n *nodeModel) {
+		fmt.Fprintf(bw, "%s%s%s\n", indent, ids[n.id], fmt.Sprintf(shapes[n.id], mermaidLabel(n.label)))
+	}
+	var writeGroup func(indent string, cluster int)
+	writeGroup = func(indent string, cluster int) {
+		fmt.Fprintf(bw, "%ssubgraph group%d [%s]\n", indent, cluster, mermaidLabel(m.clusters[cluster].name))
+		for _, i := range m.children(cluster) {
+			writeGroup(indent+"    ", i)
+		}
+		for _, n := range m.nodes {
+			if n.cluster == cluster {
+				writeNode(indent+"    ", n)
+			}
+		}
+		fmt.Fprintf(bw, "%send\n", indent)
+	}
+	for _, i := range m.children(-1) {
+		writeGroup("    ", i)
+	}
+	for _, n := range m.nodes {
+		if n.cluster < 0 {
+			writeNode("    ", n)
+		}
+	}
+	for _, e := range m.edges {
+		arrow := "-->"
+		if e.taken {
+			arrow = "==>"
+		}
+		if e.label != "" {
+			fmt.Fprintf(bw, "    %s %s|%s| %s\n", ids[e.from], arrow, e.label, ids[e.to])
+		} else {
+			fmt.Fprintf(bw, "    %s %s %s\n", ids[e.from], arrow, ids[e.to])
+		}
+	}
+	for i, c := range m.clusters {
+		fill := "lightgrey"
+		if c.color != "" {
+			fill = c.color
+		}
+		fmt.Fprintf(bw, "    style group%d fill:%s\n", i, fill)
+	}
+	for _, n := range m.nodes {
+		fmt.Fprintf(bw, "    style %s fill:%s", ids[n.id], color(n.colorScheme, n.color))
+		if n.fontColor != "" {
+			fmt.Fprintf(bw, ",color:%s", color(n.colorScheme, n.fontColor))
+		}
+		fmt.Fprintln(bw)
+	}
+	return bw.Flush()
+}
+
+// mermaidIDs maps Task IDs to Mermaid node IDs, which can't contain dashes or be reserved words such as "end"
+func mermaidIDs(m *graphModel) map[string]string {
+	ids := make(map[string]string)
+	used := make(map[string]bool)
+	for _, n := range m.nodes {
+		base := "task_" + strings.Map(func(r rune) rune {
+			if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+				return r
+			}
+			return '_'
+		}, n.id)
+		id := base
+		for i := 2; used[id]; i++ {
+			id = fmt.Sprintf("%s_%d", base, i)
+		}
+		used[id] = true
+		ids[n.id] = id
+	}
+	return ids
+}
+
+// mermaidLabel quotes a label, escaping the characters Mermaid can't show inside quotes
+func mermaidLabel(label string) string {
+	label = strings.ReplaceAll(label, "\"", "#quot;")
+	label = strings.ReplaceAll(label, "\n", "<br>")
+	return fmt.Sprintf("\"%s\"", label)
+}
+
+// color returns the hex colour for a colour in one of the Graphviz colour schemes used for nodes
+func color(scheme string, index string) string {
+	var i int
+	fmt.Sscanf(index, "%d", &i)
+	if i < 1 || i > len(colors[scheme]) {
+		return "#ffffff"
+	}
+	return colors[scheme][i-1]
+}
@@ -0,0 +1,67 @@
+package rendering
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/futrli/graphflow"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteMermaid(t *testing.T) {
+	gf := buildGraphflow()
+	gf.NewTaskGroup("Forecasting").AddTasks(gf.Task("forecast-rain"), gf.Task("forecast-sun"))
+
+	var buf bytes.Buffer
+	err := WriteMermaid(&buf, gf)
+
+	assert.Nil(t, err)
+	assert.Equal(t, `flowchart TD
+    subgraph group0 ["Forecasting"]
+        task_forecast_rain["Forecast Rain"]
+        task_forecast_sun["Forecast Sun"]
+    end
+    task_start(["Start"])
+    task_is_the_sky_cloudy{"Is the sky cloudy?"}
+    task_end(["End"])
+    task_start --> task_is_the_sky_cloudy
+    task_is_the_sky_cloudy -->|YES| task_forecast_rain
+    task_is_the_sky_cloudy -->|NO| task_forecast_sun
+    task_forecast_rain --> task_end
+    task_forecast_sun --> task_end
+    style group0 fill:lightgrey
+    style task_start fill:#fdbf6f
+    style task_is_the_sky_cloudy fill:#b2df8a
+    style task_forecast_rain fill:#cab2d6
+    style task_forecast_sun fill:#cab2d6
+    style task_end fill:#fdbf6f
+`, buf.String())
+}
+
+func TestWriteMermaidPath(t *testing.T) {
+	ctx := new(graphflow.ExecutionContext)
+	ctx.Set("Sky", "Cloudy")
+	gf := buildGraphflow()
+	gf.Run(ctx)
+
+	var buf bytes.Buffer
+	err := WriteMermaidPath(&buf, gf, gf.Result())
+
+	assert.Nil(t, err)
+	assert.Contains(t, buf.String(), "    task_is_the_sky_cloudy ==>|YES| task_forecast_rain\n")
+	assert.Contains(t, buf.String(), "    task_is_the_sky_cloudy -->|NO| task_forecast_sun\n")
+	assert.Contains(t, buf.String(), "    task_forecast_rain ==> task_end\n")
+	assert.Contains(t, buf.String(), "    style task_forecast_sun fill:#f0f0f0,color:#bdbdbd\n")
+	assert.Contains(t, buf.String(), "    style task_forecast_rain fill:#cab2d6\n")
+}
+
+func TestMermaidIDsAreUnique(t *testing.T) {
+	var gf graphflow.Graphflow
+	gf.AddTaskWithID("forecast-sun", new(ForecastSun))
+	gf.AddTaskWithID("forecast_sun", new(ForecastSun))
+
+	ids := mermaidIDs(newGraphModel(&gf, nil, "", RenderOptions{}))
+
+	assert.Equal(t, "task_forecast_sun", ids["forecast-sun"])
+	assert.Equal(t, "task_forecast_sun_2", ids["forecast_sun"])
+}
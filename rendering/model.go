@@ -0,0 +1,475 @@
+package rendering
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/futrli/graphflow"
+)
+
+// graphModel is a description of how a graphflow should be drawn, independent of the output it's drawn to.
+// Nodes, clusters and edges are held in the order they were added to the graphflow, so the output is the same every
+// time the same graphflow is drawn.
+type graphModel struct {
+	clusters []*clusterModel
+	nodes    []*nodeModel
+	edges    []*edgeModel
+	// description is drawn as an underlined note at the top of the graph, if it's set
+	description string
+	theme       *Theme
+	rankDir     RankDir
+	fontName    string
+	dpi         float64
+}
+
+type clusterModel struct {
+	name string
+	// parent is the index of the cluster this one is nested inside, or -1 if it isn't nested
+	parent      int
+	color       string
+	description string
+}
+
+// bgColor returns the colour a cluster is filled with, which is its TaskGroup's colour if it has one
+func (c *clusterModel) bgColor(theme *Theme) string {
+	if c.color != "" {
+		return c.color
+	}
+	return theme.Cluster
+}
+
+// children returns the indexes of the clusters nested directly inside the cluster with the given index, which is
+// -1 for the clusters that aren't nested
+func (m *graphModel) children(cluster int) []int {
+	children := []int{}
+	for i, c := range m.clusters {
+		if c.parent == cluster {
+			children = append(children, i)
+		}
+	}
+	return children
+}
+
+type nodeModel struct {
+	id          string
+	label       string
+	tooltip     string
+	url         string
+	colorScheme string
+	color       string
+	fontColor   string
+	kind        TaskKind
+	shape       string
+	// detail is drawn under the label, eg the time a Task took to execute on the path being shown
+	detail string
+	// cluster is the index of the node's cluster, or -1 if it isn't in one
+	cluster int
+}
+
+type edgeModel struct {
+	from      string
+	to        string
+	condition graphflow.PathCondition
+	label     string
+	// taken is set if the edge was followed in the path being shown
+	taken bool
+	// steps holds the numbers of the Steps in which the edge was followed
+	steps []int
+	// hits is the number of runs the edge was followed in, shown if the coverage of many runs is being drawn
+	hits     int
+	showHits bool
+	// dashed is set for edges that were never followed when coverage is being drawn
+	dashed bool
+}
+
+// id identifies the edge in SVG output by the Task it leaves and its PathCondition, eg "path-is-the-sky-cloudy:YES"
+func (e *edgeModel) id() string {
+	return edgeID(e.from, e.condition)
+}
+
+func edgeID(from string, condition graphflow.PathCondition) string {
+	return fmt.Sprintf("path-%s:%s", from, graphflow.PathConditionName[condition])
+}
+
+// nodeID identifies a Task's node in SVG output, eg "task-is-the-sky-cloudy". Task IDs are prefixed so they can't
+// clash with the IDs of the other elements on a page, such as the sidebar written by WriteHTML.
+func nodeID(taskID string) string {
+	return "task-" + taskID
+}
+
+// fullLabel returns the label of the node with its detail on the line below, if it has any
+func (n *nodeModel) fullLabel() string {
+	if n.detail == "" {
+		return n.label
+	}
+	return fmt.Sprintf("%s\n%s", n.label, n.detail)
+}
+
+// fullLabel returns the label of the edge prefixed with the numbers of the Steps it was followed in, eg "#2 YES", or
+// with the number of times it was followed when coverage is being drawn, eg "×3 YES"
+func (e *edgeModel) fullLabel() string {
+	parts := []string{}
+	for _, step := range e.steps {
+		parts = append(parts, fmt.Sprintf("#%d", step))
+	}
+	if e.showHits {
+		parts = []string{fmt.Sprintf("×%d", e.hits)}
+	}
+	steps := strings.Join(parts, ", ")
+	if steps == "" || e.label == "" {
+		return steps + e.label
+	}
+	return fmt.Sprintf("%s %s", steps, e.label)
+}
+
+// pathModel is the path taken through a graphflow, by Task ID
+type pathModel struct {
+	tasks map[string]bool
+	edges map[edgeKey]bool
+	// steps holds the numbers of the Steps in which each edge was followed, if the path was traced
+	steps map[edgeKey][]int
+	// durations hold the time each Task took, if the path was traced
+	durations map[string]time.Duration
+	// failed is the ID of the Task that stopped the run with an error, if one did
+	failed string
+	err    string
+	// coverage is set if the path is the coverage of many runs, with hits holding the number of times each edge
+	// was followed
+	coverage bool
+	hits     map[edgeKey]int
+	// counts holds the number of times each Task was executed, if the path is the coverage of many runs
+	counts map[string]int
+}
+
+type edgeKey struct {
+	from      string
+	condition graphflow.PathCondition
+}
+
+// tracedPath returns the path recorded in a RunResult, including the Paths followed between its Steps, the order
+// they were followed in, how long each Task took and which Task failed, if any
+func tracedPath(result *graphflow.RunResult) *pathModel {
+	p := &pathModel{
+		tasks:     make(map[string]bool),
+		edges:     make(map[edgeKey]bool),
+		steps:     make(map[edgeKey][]int),
+		durations: make(map[string]time.Duration),
+		failed:    result.Next,
+		err:       result.Error,
+	}
+	for i, step := range result.Steps {
+		p.tasks[step.TaskID] = true
+		p.durations[step.TaskID] = step.Duration
+		// the last Step only leads somewhere if the run went on to fail
+		if i < len(result.Steps)-1 || result.Next != "" {
+			key := edgeKey{from: step.TaskID, condition: step.ExitPath}
+			p.edges[key] = true
+			p.steps[key] = append(p.steps[key], i+1)
+		}
+	}
+	return p
+}
+
+// coveragePath returns the Tasks and Paths covered by many runs, with the number of times each was executed or
+// followed
+func coveragePath(report *graphflow.CoverageReport) *pathModel {
+	p := &pathModel{
+		tasks:    make(map[string]bool),
+		edges:    make(map[edgeKey]bool),
+		counts:   make(map[string]int),
+		coverage: true,
+		hits:     make(map[edgeKey]int),
+	}
+	for _, t := range report.Tasks {
+		p.tasks[t.TaskID] = t.Hits > 0
+		p.counts[t.TaskID] = t.Hits
+	}
+	for _, path := range report.Paths {
+		condition, err := graphflow.ParsePathCondition(path.Condition)
+		if err != nil {
+			continue
+		}
+		key := edgeKey{from: path.From, condition: condition}
+		p.edges[key] = path.Hits > 0
+		p.hits[key] = path.Hits
+	}
+	return p
+}
+
+// taskDetail describes how a Task on a path was executed: how long it took on a traced path, eg "1.5ms", or how
+// many times it was executed for coverage, eg "×3"
+func (p *pathModel) taskDetail(id string) string {
+	if p.coverage {
+		return fmt.Sprintf("×%d", p.counts[id])
+	}
+	if id == p.failed {
+		if p.err == "" {
+			return "Failed"
+		}
+		return fmt.Sprintf("Failed: %s", p.err)
+	}
+	if d := p.durations[id]; d > 0 {
+		return roundDuration(d).String()
+	}
+	return ""
+}
+
+// roundDuration rounds a duration to three significant figures, which is plenty for reading off a graph
+func roundDuration(d time.Duration) time.Duration {
+	unit := time.Duration(1)
+	for limit := time.Duration(1000); d >= limit && limit < time.Hour; limit *= 10 {
+		unit *= 10
+	}
+	return d.Round(unit)
+}
+
+// contextDescription describes the values of the given keys, for showing alongside a path
+func contextDescription(values map[string]interface{}, contextKeysToRender ...string) string {
+	desc := ""
+	for _, k := range contextKeysToRender {
+		desc = fmt.Sprintf("%s\n%s = %v", desc, k, values[k])
+	}
+	if desc != "" {
+		desc = fmt.Sprintf("This is the path taken when:\n%s", desc)
+	}
+	return desc
+}
+
+// taskTooltip describes a Task with its name, ID and Metadata
+func taskTooltip(gf *graphflow.Graphflow, t graphflow.TaskIntf, metadata graphflow.Metadata) string {
+	lines := []string{fmt.Sprintf("%s (%s)", t.String(), gf.TaskID(t))}
+	if metadata.Description != "" {
+		lines = append(lines, metadata.Description)
+	}
+	if metadata.Owner != "" {
+		lines = append(lines, fmt.Sprintf("Owner: %s", metadata.Owner))
+	}
+	if len(metadata.Tags) > 0 {
+		lines = append(lines, fmt.Sprintf("Tags: %s", strings.Join(metadata.Tags, ", ")))
+	}
+	if metadata.SLA > 0 {
+		lines = append(lines, fmt.Sprintf("SLA: %s", metadata.SLA))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// Linker can be implemented by Tasks to make their node a link in SVG output, eg to the Task's documentation. Tasks
+// that don't implement it link to the DocURL in their Metadata.
+type Linker interface {
+	URL() string
+}
+
+// newGraphModel describes the Tasks and Paths of a graphflow, coloured by the kind of Task. If a path is given,
+// Tasks that aren't on it are greyed out, and the description is shown at the top of the graph.
+func newGraphModel(gf *graphflow.Graphflow, path *pathModel, description string, opts RenderOptions) *graphModel {
+	showPath := path != nil
+	theme := opts.theme()
+	m := &graphModel{
+		description: description,
+		theme:       theme,
+		rankDir:     opts.RankDir,
+		fontName:    opts.FontName,
+		dpi:         opts.DPI,
+	}
+	clusters := make(map[graphflow.TaskIntf]int)
+	groups := make(map[*graphflow.TaskGroup]int)
+	for i, tg := range gf.TaskGroups() {
+		c := &clusterModel{name: tg.Name(), parent: -1, color: tg.Color(), description: tg.Description()}
+		if parent, nested := groups[tg.Parent()]; nested {
+			c.parent = parent
+		}
+		m.clusters = append(m.clusters, c)
+		groups[tg] = i
+		for _, t := range tg.Tasks() {
+			clusters[t] = i
+		}
+	}
+	targets := make(map[graphflow.TaskIntf]bool)
+	for _, edge := range gf.Paths() {
+		for _, to := range edge {
+			targets[to] = true
+		}
+	}
+	for _, t := range gf.Tasks() {
+		metadata := gf.Metadata(t)
+		n := &nodeModel{
+			id:      gf.TaskID(t),
+			label:   t.String(),
+			tooltip: taskTooltip(gf, t, metadata),
+			url:     metadata.DocURL,
+			kind:    UnconnectedKind,
+			cluster: -1,
+		}
+		if linker, ok := t.(Linker); ok {
+			n.url = linker.URL()
+		}
+		if i, exists := clusters[t]; exists {
+			n.cluster = i
+		}
+		color := theme.Unconnected
+		if showPath {
+			color = theme.Inactive
+		}
+		edge := gf.Paths()[t]
+		if endTask, isEndTask := t.(*graphflow.EndTask); isEndTask && targets[t] {
+			n.kind = EndKind
+			color = outcomeColor(gf, endTask, theme)
+		}
+		if len(edge) > 0 {
+			n.kind = ActionKind
+			if _, isStartTask := t.(*graphflow.StartTask); isStartTask {
+				n.kind = StartKind
+			} else {
+				for label := range edge {
+					if label == graphflow.YES || label == graphflow.NO {
+						n.kind = QuestionKind
+						break
+					}
+				}
+			}
+			color = theme.color(n.kind)
+		}
+		for _, tag := range metadata.Tags {
+			if tagColor, exists := opts.TagColors[tag]; exists {
+				color = tagColor
+				break
+			}
+		}
+		if showPath && (targets[t] || len(edge) > 0) && !path.tasks[n.id] {
+			color = theme.Inactive
+			if path.coverage {
+				color = theme.Failed
+			}
+		}
+		if len(opts.Tags) > 0 && !metadata.HasTag(opts.Tags...) {
+			color = theme.Inactive
+		}
+		if showPath {
+			if n.id == path.failed {
+				color = theme.Failed
+			}
+			n.detail = path.taskDetail(n.id)
+		}
+		n.colorScheme, n.color, n.fontColor = color.Scheme, color.Fill, color.Font
+		n.shape = opts.Shapes[n.kind]
+		m.nodes = append(m.nodes, n)
+	}
+	for _, p := range gf.OrderedPaths() {
+		e := &edgeModel{
+			from:      gf.TaskID(p.From),
+			to:        gf.TaskID(p.To),
+			condition: p.Condition,
+		}
+		if p.Condition != graphflow.ALWAYS {
+			e.label = graphflow.PathConditionName[p.Condition]
+		}
+		if showPath {
+			key := edgeKey{from: e.from, condition: e.condition}
+			e.taken = path.edges[key]
+			e.steps = path.steps[key]
+			if path.coverage {
+				e.hits, e.showHits, e.dashed = path.hits[key], true, !e.taken
+			}
+		}
+		m.edges = append(m.edges, e)
+	}
+	if opts.Legend {
+		m.addLegend(gf, path, opts)
+	}
+	return m
+}
+
+// addLegend adds a Legend cluster to the graph, with a node in the colour and shape of each kind of Task, each named
+// outcome and, when they're used, the colours of the Tasks off the path being shown, and a note explaining the Path
+// labels
+func (m *graphModel) addLegend(gf *graphflow.Graphflow, path *pathModel, opts RenderOptions) {
+	m.clusters = append(m.clusters, &clusterModel{name: "Legend", parent: -1})
+	cluster := len(m.clusters) - 1
+	add := func(name string, label string, color Color, kind TaskKind) {
+		m.nodes = append(m.nodes, &nodeModel{
+			id:          m.legendID(name),
+			label:       label,
+			colorScheme: color.Scheme,
+			color:       color.Fill,
+			fontColor:   color.Font,
+			kind:        kind,
+			shape:       opts.Shapes[kind],
+			cluster:     cluster,
+		})
+	}
+	for _, kind := range []TaskKind{StartKind, QuestionKind, ActionKind, EndKind, UnconnectedKind} {
+		add(strings.ToLower(TaskKindName[kind]), TaskKindName[kind], m.theme.color(kind), kind)
+	}
+	outcomes := make(map[string]bool)
+	for _, t := range gf.Tasks() {
+		endTask, isEndTask := t.(*graphflow.EndTask)
+		if !isEndTask || endTask.Outcome() == "" || outcomes[endTask.Outcome()] || len(m.theme.Outcomes) == 0 {
+			continue
+		}
+		outcomes[endTask.Outcome()] = true
+		add(fmt.Sprintf("end-%d", len(outcomes)), fmt.Sprintf("End: %s", endTask.Outcome()), outcomeColor(gf, endTask, m.theme), EndKind)
+	}
+	if path != nil && path.coverage {
+		add("never-run", "Never run", m.theme.Failed, ActionKind)
+	} else if path != nil {
+		add("not-on-path", "Not on path", m.theme.Inactive, ActionKind)
+		add("failed", "Failed", m.theme.Failed, ActionKind)
+	}
+	if len(opts.Tags) > 0 {
+		add("untagged", "Untagged", m.theme.Inactive, ActionKind)
+	}
+	note := &nodeModel{
+		id:        m.legendID("paths"),
+		label:     "YES / NO: the answer to a question\nERROR: followed when the Task returns an error\nno label: always followed",
+		color:     "white",
+		fontColor: m.theme.Font,
+		shape:     "note",
+		cluster:   cluster,
+	}
+	if m.theme.Background != "" {
+		note.color = m.theme.Background
+	}
+	m.nodes = append(m.nodes, note)
+}
+
+// legendID returns the ID of a node in the legend. It's prefixed with "legend:", which Task names can't be turned
+// into, and numbered if a Task was added with that ID anyway, so the legend never clashes with a Task.
+func (m *graphModel) legendID(name string) string {
+	base := "legend:" + name
+	id := base
+	for i := 2; m.hasNode(id); i++ {
+		id = fmt.Sprintf("%s-%d", base, i)
+	}
+	return id
+}
+
+// hasNode returns true if the graph has a node with the given ID
+func (m *graphModel) hasNode(id string) bool {
+	for _, n := range m.nodes {
+		if n.id == id {
+			return true
+		}
+	}
+	return false
+}
+
+// outcomeColor returns the Color for an EndTask. The default EndTask is coloured like the StartTask, while each
+// named outcome gets its own colour in the order the outcomes were added to the graphflow
+func outcomeColor(gf *graphflow.Graphflow, endTask *graphflow.EndTask, theme *Theme) Color {
+	if endTask.Outcome() == "" || len(theme.Outcomes) == 0 {
+		return theme.End
+	}
+	i := 0
+	for _, t := range gf.Tasks() {
+		e, isEndTask := t.(*graphflow.EndTask)
+		if !isEndTask || e.Outcome() == "" {
+			continue
+		}
+		if e == endTask {
+			break
+		}
+		i++
+	}
+	return theme.Outcomes[i%len(theme.Outcomes)]
+}
@@ -0,0 +1,229 @@
+package rendering
+
+import (
+	"fmt"
+
+	"github.com/futrli/graphflow"
+)
+
+// Color is how a node is filled, as a Graphviz colour name or hex value, or an index into a Graphviz colour scheme
+// if Scheme is set. Font is the colour of the node's label, in the same scheme, and defaults to black.
+type Color struct {
+	Scheme string
+	Fill   string
+	Font   string
+}
+
+// Theme sets the colours a graphflow is drawn in
+type Theme struct {
+	// Start, Question, Action and End colour the kinds of Task
+	Start    Color
+	Question Color
+	Action   Color
+	End      Color
+	// Outcomes colour the EndTasks with named outcomes, in the order they were added to the graphflow
+	Outcomes []Color
+	// Unconnected colours Tasks that have no Paths
+	Unconnected Color
+	// Inactive colours Tasks that aren't on the path being shown
+	Inactive Color
+	// Failed colours the Task that stopped the run being shown with an error
+	Failed Color
+	// Background, Cluster, Edge and Font are Graphviz colours for the graph, TaskGroups, Paths and any text that
+	// isn't inside a node. Empty values are left to Graphviz.
+	Background string
+	Cluster    string
+	Edge       string
+	Font       string
+}
+
+// DefaultTheme is the theme graphflows have always been drawn in
+var DefaultTheme = &Theme{
+	Start:       Color{Scheme: "paired10", Fill: "7"}, // orange
+	Question:    Color{Scheme: "paired10", Fill: "3"}, // green
+	Action:      Color{Scheme: "paired10", Fill: "9"}, // mauve
+	End:         Color{Scheme: "paired10", Fill: "7"}, // orange
+	Outcomes:    schemeColors("pastel28", 8),
+	Unconnected: Color{Scheme: "paired10", Fill: "6"},          // red
+	Inactive:    Color{Scheme: "greys3", Fill: "1", Font: "2"}, // grey
+	Failed:      Color{Scheme: "paired10", Fill: "6"},          // red
+	Cluster:     "lightgrey",
+}
+
+// ColorBlindTheme uses the Okabe-Ito palette, whose colours can be told apart with any common colour blindness
+var ColorBlindTheme = &Theme{
+	Start:    Color{Fill: "#E69F00"}, // orange
+	Question: Color{Fill: "#56B4E9"}, // sky blue
+	Action:   Color{Fill: "#F0E442"}, // yellow
+	End:      Color{Fill: "#E69F00"}, // orange
+	Outcomes: []Color{
+		{Fill: "#009E73", Font: "#FFFFFF"}, // bluish green
+		{Fill: "#CC79A7"},                  // reddish purple
+		{Fill: "#0072B2", Font: "#FFFFFF"}, // blue
+	},
+	Unconnected: Color{Fill: "#D55E00", Font: "#FFFFFF"}, // vermillion
+	Inactive:    Color{Fill: "#EEEEEE", Font: "#999999"},
+	Failed:      Color{Fill: "#D55E00", Font: "#FFFFFF"}, // vermillion
+	Cluster:     "#DDDDDD",
+}
+
+// DarkTheme draws light text and muted nodes on a dark background
+var DarkTheme = &Theme{
+	Start:    Color{Fill: "#D08770"},
+	Question: Color{Fill: "#A3BE8C"},
+	Action:   Color{Fill: "#B48EAD"},
+	End:      Color{Fill: "#D08770"},
+	Outcomes: []Color{
+		{Fill: "#88C0D0"},
+		{Fill: "#EBCB8B"},
+		{Fill: "#8FBCBB"},
+		{Fill: "#81A1C1"},
+	},
+	Unconnected: Color{Fill: "#BF616A", Font: "#ECEFF4"},
+	Inactive:    Color{Fill: "#3B4252", Font: "#7B88A1"},
+	Failed:      Color{Fill: "#BF616A", Font: "#ECEFF4"},
+	Background:  "#2E3440",
+	Cluster:     "#434C5E",
+	Edge:        "#D8DEE9",
+	Font:        "#ECEFF4",
+}
+
+// TaskKind is the kind of a Task, as far as rendering is concerned
+type TaskKind int
+
+const (
+	// StartKind is a StartTask
+	StartKind TaskKind = iota
+	// QuestionKind is a Task with YES or NO Paths
+	QuestionKind
+	// ActionKind is any other Task with Paths leaving it
+	ActionKind
+	// EndKind is an EndTask
+	EndKind
+	// UnconnectedKind is a Task with no Paths leaving it that isn't an EndTask
+	UnconnectedKind
+)
+
+// TaskKindName maps the TaskKinds to the names shown in the legend
+var TaskKindName = map[TaskKind]string{
+	StartKind:       "Start",
+	QuestionKind:    "Question",
+	ActionKind:      "Action",
+	EndKind:         "End",
+	UnconnectedKind: "Unconnected",
+}
+
+// RankDir is the direction a graphflow is laid out in, from its StartTask towards its EndTasks
+type RankDir string
+
+const (
+	// TopToBottom lays graphflows out downwards, which is the default
+	TopToBottom RankDir = "TB"
+	// LeftToRight lays graphflows out across the page
+	LeftToRight RankDir = "LR"
+	// BottomToTop lays graphflows out upwards
+	BottomToTop RankDir = "BT"
+	// RightToLeft lays graphflows out backwards across the page
+	RightToLeft RankDir = "RL"
+)
+
+// RenderOptions configures how a graphflow is drawn. The zero value draws a png in the default theme.
+type RenderOptions struct {
+	// Format is the output format, defaulting to PNG. It's ignored by WriteDOT.
+	Format Format
+	// Theme sets the colours used, defaulting to DefaultTheme
+	Theme *Theme
+	// RankDir sets the direction of the layout, defaulting to TopToBottom
+	RankDir RankDir
+	// FontName is the font used for all text, eg "Helvetica"
+	FontName string
+	// Shapes sets the Graphviz node shape for kinds of Task, eg {QuestionKind: "diamond"}. Kinds that aren't set
+	// are drawn as ellipses.
+	Shapes map[TaskKind]string
+	// DPI sets the resolution of png and jpeg output
+	DPI float64
+	// Legend adds a key explaining the node colours and Path labels
+	Legend bool
+	// Run is a recorded run of the graphflow whose path is highlighted, eg one unmarshalled from storage
+	Run *graphflow.RunResult
+	// ShowPath highlights the path taken by the most recent Run of the graphflow, if Run isn't set
+	ShowPath bool
+	// Coverage draws the coverage collected from many runs, if Run and ShowPath aren't set. Tasks that were never
+	// executed are drawn in the Theme's Failed colour, Paths that were never followed are dashed, and both are
+	// labelled with the number of times they were covered.
+	Coverage *graphflow.CoverageReport
+	// Tags dims every Task that doesn't have any of these tags in its Metadata, using the Theme's Inactive colour
+	Tags []string
+	// TagColors colours the Tasks with a tag in their Metadata, using the colour of the first of their tags found
+	TagColors map[string]Color
+	// ContextKeys are rendered with their values at the top of the graph when a path is highlighted. The values
+	// are taken from the last Step of a Run, if it was recorded with RecordContext, or else from the graphflow's
+	// ExecutionContext when ShowPath is set.
+	ContextKeys []string
+}
+
+// DOTOptions configures the DOT source written by WriteDOT
+type DOTOptions = RenderOptions
+
+// path returns the path to highlight and the description of it to show, if the options highlight one
+func (opts RenderOptions) path(gf *graphflow.Graphflow) (*pathModel, string) {
+	switch {
+	case opts.Run != nil:
+		var values map[string]interface{}
+		for _, step := range opts.Run.Steps {
+			if step.Context != nil {
+				values = step.Context
+			}
+		}
+		if values == nil {
+			return tracedPath(opts.Run), ""
+		}
+		return tracedPath(opts.Run), contextDescription(values, opts.ContextKeys...)
+	case opts.ShowPath:
+		result := gf.Result()
+		if result == nil {
+			result = new(graphflow.RunResult)
+		}
+		var values map[string]interface{}
+		if gf.GetContext() != nil {
+			values = gf.GetContext().Snapshot()
+		}
+		return tracedPath(result), contextDescription(values, opts.ContextKeys...)
+	case opts.Coverage != nil:
+		return coveragePath(opts.Coverage), fmt.Sprintf("Coverage of %d runs: %.1f%% of Tasks, %.1f%% of Paths",
+			opts.Coverage.Runs, opts.Coverage.TaskPercent(), opts.Coverage.PathPercent())
+	}
+	return nil, ""
+}
+
+// theme returns the Theme the options use
+func (opts RenderOptions) theme() *Theme {
+	if opts.Theme == nil {
+		return DefaultTheme
+	}
+	return opts.Theme
+}
+
+// color returns the Theme's Color for a kind of Task
+func (theme *Theme) color(kind TaskKind) Color {
+	switch kind {
+	case StartKind:
+		return theme.Start
+	case QuestionKind:
+		return theme.Question
+	case ActionKind:
+		return theme.Action
+	case EndKind:
+		return theme.End
+	}
+	return theme.Unconnected
+}
+
+// schemeColors returns the first n colours of a Graphviz colour scheme
+func schemeColors(scheme string, n int) []Color {
+	colors := []Color{}
+	for i := 1; i <= n; i++ {
+		colors = append(colors, Color{Scheme: scheme, Fill: fmt.Sprintf("%d", i)})
+	}
+	return colors
+}
@@ -0,0 +1,151 @@
+package rendering
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/futrli/graphflow"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRenderWithOptions(t *testing.T) {
+	for _, theme := range []*Theme{DefaultTheme, ColorBlindTheme, DarkTheme} {
+		gf := buildGraphflow()
+
+		buf, err := Render(gf, RenderOptions{
+			Theme:    theme,
+			RankDir:  LeftToRight,
+			FontName: "Helvetica",
+			Shapes:   map[TaskKind]string{QuestionKind: "diamond", ActionKind: "box"},
+			DPI:      150,
+			Legend:   true,
+		})
+
+		assert.Nil(t, err)
+		assert.NotEmpty(t, buf.Bytes())
+	}
+}
+
+func TestRenderWithOptionsAsDOT(t *testing.T) {
+	gf := buildGraphflow()
+
+	buf, err := Render(gf, RenderOptions{
+		Format:   DOT,
+		Theme:    DarkTheme,
+		RankDir:  LeftToRight,
+		FontName: "Helvetica",
+		Shapes:   map[TaskKind]string{QuestionKind: "diamond"},
+	})
+
+	assert.Nil(t, err)
+	dot := buf.String()
+	assert.Contains(t, dot, "\tgraph [rankdir=\"LR\", bgcolor=\"#2E3440\", fontcolor=\"#ECEFF4\", fontname=\"Helvetica\"];\n")
+	assert.Contains(t, dot, "\tnode [fontname=\"Helvetica\"];\n")
+	assert.Contains(t, dot, "\tedge [fontcolor=\"#ECEFF4\", color=\"#D8DEE9\", fontname=\"Helvetica\"];\n")
+	assert.Contains(t, dot, "\"is-the-sky-cloudy\" [label=\"Is the sky cloudy?\", style=\"filled\", color=\"#A3BE8C\", shape=\"diamond\", tooltip=\"Is the sky cloudy? (is-the-sky-cloudy)\", id=\"task-is-the-sky-cloudy\"];")
+	assert.Contains(t, dot, "\"start\" [label=\"Start\", style=\"filled\", color=\"#D08770\", tooltip=\"Start (start)\", id=\"task-start\"];")
+	assert.NotContains(t, dot, "Legend")
+}
+
+func TestRenderLegend(t *testing.T) {
+	gf := buildGraphflow()
+
+	var buf bytes.Buffer
+	err := WriteDOT(&buf, gf, DOTOptions{Theme: ColorBlindTheme, Legend: true})
+
+	assert.Nil(t, err)
+	dot := buf.String()
+	assert.Contains(t, dot, "subgraph \"cluster_0\" {\n\t\tlabel=\"Legend\";")
+	assert.Contains(t, dot, "\"legend:question\" [label=\"Question\", style=\"filled\", color=\"#56B4E9\", id=\"task-legend:question\"];")
+	assert.Contains(t, dot, "\"legend:action\" [label=\"Action\", style=\"filled\", color=\"#F0E442\", id=\"task-legend:action\"];")
+	assert.Contains(t, dot, "\"legend:paths\" [label=\"YES / NO: the answer to a question\\nERROR: followed when the Task returns an error\\nno label: always followed\"")
+}
+
+func TestRenderLegendWithOutcomesAndPath(t *testing.T) {
+	var gf graphflow.Graphflow
+	start := gf.AddTask(new(graphflow.StartTask))
+	isTheSkyCloudy := gf.AddTask(new(IsTheSkyCloudy))
+	takeUmbrella := gf.AddTask(graphflow.NewEndTask("Take Umbrella"))
+	wearSunglasses := gf.AddTask(graphflow.NewEndTask("Wear Sunglasses"))
+	gf.AddPath(start, graphflow.ALWAYS, isTheSkyCloudy)
+	gf.AddPath(isTheSkyCloudy, graphflow.YES, takeUmbrella)
+	gf.AddPath(isTheSkyCloudy, graphflow.NO, wearSunglasses)
+
+	var buf bytes.Buffer
+	err := WriteDOT(&buf, &gf, DOTOptions{Theme: ColorBlindTheme, Legend: true, ShowPath: true})
+
+	assert.Nil(t, err)
+	dot := buf.String()
+	assert.Contains(t, dot, "\"legend:unconnected\" [label=\"Unconnected\", style=\"filled\", color=\"#D55E00\", fontcolor=\"#FFFFFF\"")
+	assert.Contains(t, dot, "\"legend:end-1\" [label=\"End: Take Umbrella\", style=\"filled\", color=\"#009E73\", fontcolor=\"#FFFFFF\"")
+	assert.Contains(t, dot, "\"legend:end-2\" [label=\"End: Wear Sunglasses\", style=\"filled\", color=\"#CC79A7\"")
+	assert.Contains(t, dot, "\"legend:not-on-path\" [label=\"Not on path\", style=\"filled\", color=\"#EEEEEE\", fontcolor=\"#999999\"")
+	assert.Contains(t, dot, "\"legend:failed\" [label=\"Failed\", style=\"filled\", color=\"#D55E00\", fontcolor=\"#FFFFFF\"")
+}
+
+func TestLegendDoesNotClashWithTasks(t *testing.T) {
+	gf := buildGraphflow()
+	gf.AddTaskWithID("legend-start", new(ForecastRain))
+	gf.AddTaskWithID("legend:question", new(ForecastSun))
+
+	var buf bytes.Buffer
+	err := WriteDOT(&buf, gf, DOTOptions{Legend: true})
+
+	assert.Nil(t, err)
+	dot := buf.String()
+	assert.Contains(t, dot, "\"legend-start\" [label=\"Forecast Rain\"")
+	assert.Contains(t, dot, "\"legend:start\" [label=\"Start\"")
+	assert.Contains(t, dot, "\"legend:question\" [label=\"Forecast Sun\"")
+	assert.Contains(t, dot, "\"legend:question-2\" [label=\"Question\"")
+}
+
+func TestDefaultOptionsMatchRenderGraph(t *testing.T) {
+	gf := buildGraphflow()
+
+	var withOptions bytes.Buffer
+	WriteDOT(&withOptions, gf, DOTOptions{Theme: DefaultTheme})
+	var withoutOptions bytes.Buffer
+	WriteDOT(&withoutOptions, gf, DOTOptions{})
+
+	assert.Equal(t, withoutOptions.String(), withOptions.String())
+	assert.NotContains(t, withOptions.String(), "graph [")
+}
+
+func TestTagColorsAndFilters(t *testing.T) {
+	gf := buildGraphflow()
+	gf.SetMetadata(gf.Task("forecast-rain"), graphflow.Metadata{Tags: []string{"rain"}})
+	gf.SetMetadata(gf.Task("forecast-sun"), graphflow.Metadata{Tags: []string{"sun"}})
+
+	buf, err := Render(gf, RenderOptions{
+		Format:    DOT,
+		TagColors: map[string]Color{"rain": {Fill: "lightblue"}},
+	})
+
+	assert.Nil(t, err)
+	assert.Contains(t, buf.String(), `"forecast-rain" [label="Forecast Rain", style="filled", color="lightblue", tooltip="Forecast Rain (forecast-rain)\nTags: rain", id="task-forecast-rain"];`)
+	assert.Contains(t, buf.String(), `"forecast-sun" [label="Forecast Sun", style="filled", colorscheme="paired10", color="9", tooltip="Forecast Sun (forecast-sun)\nTags: sun", id="task-forecast-sun"];`)
+
+	buf, err = Render(gf, RenderOptions{Format: DOT, Tags: []string{"sun"}})
+
+	assert.Nil(t, err)
+	assert.Contains(t, buf.String(), `"forecast-rain" [label="Forecast Rain", style="filled", colorscheme="greys3", color="1", fontcolor="2", tooltip="Forecast Rain (forecast-rain)\nTags: rain", id="task-forecast-rain"];`)
+	assert.Contains(t, buf.String(), `"forecast-sun" [label="Forecast Sun", style="filled", colorscheme="paired10", color="9", tooltip="Forecast Sun (forecast-sun)\nTags: sun", id="task-forecast-sun"];`)
+}
+
+func TestMetadataTooltipsAndLinks(t *testing.T) {
+	gf := buildGraphflow()
+	gf.SetMetadata(gf.Task("forecast-rain"), graphflow.Metadata{
+		Description: "Forecasts rain",
+		Owner:       "Weather Team",
+		Tags:        []string{"rain", "external"},
+		DocURL:      "https://example.com/rain",
+		SLA:         5 * time.Second,
+	})
+
+	m := newGraphModel(gf, nil, "", RenderOptions{})
+
+	assert.Equal(t, "Forecast Rain (forecast-rain)\nForecasts rain\nOwner: Weather Team\nTags: rain, external\nSLA: 5s", m.nodes[2].tooltip)
+	assert.Equal(t, "https://example.com/rain", m.nodes[2].url)
+	assert.Equal(t, "Forecast Sun (forecast-sun)", m.nodes[3].tooltip)
+}
@@ -0,0 +1,362 @@
+package rendering
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// xdotGraph is a graph laid out by Graphviz, as written in its json format. Each object holds the xdot operations
+// that draw it, in the same coordinates as svg output.
+type xdotGraph struct {
+	BB            string       `json:"bb"`
+	Draw          []xdotOp     `json:"_draw_"`
+	LDraw         []xdotOp     `json:"_ldraw_"`
+	SubgraphCount int          `json:"_subgraph_cnt"`
+	Objects       []xdotObject `json:"objects"`
+	Edges         []xdotObject `json:"edges"`
+}
+
+// xdotObject is a cluster, node or edge of an xdotGraph
+type xdotObject struct {
+	ID        int      `json:"_gvid"`
+	Subgraphs []int    `json:"subgraphs"`
+	URL       string   `json:"URL"`
+	Pos       string   `json:"pos"`
+	Width     string   `json:"width"`
+	Height    string   `json:"height"`
+	Draw      []xdotOp `json:"_draw_"`
+	LDraw     []xdotOp `json:"_ldraw_"`
+	HDraw     []xdotOp `json:"_hdraw_"`
+	TDraw     []xdotOp `json:"_tdraw_"`
+	HLDraw    []xdotOp `json:"_hldraw_"`
+	TLDraw    []xdotOp `json:"_tldraw_"`
+}
+
+// xdotOp is a single xdot drawing operation, eg "P" for a filled polygon or "T" for text
+type xdotOp struct {
+	Op     string       `json:"op"`
+	Color  string       `json:"color"`
+	Points [][2]float64 `json:"points"`
+	Rect   []float64    `json:"rect"`
+	Pt     []float64    `json:"pt"`
+	Align  string       `json:"align"`
+	Width  float64      `json:"width"`
+	Text   string       `json:"text"`
+	Size   float64      `json:"size"`
+	Face   string       `json:"face"`
+	Style  string       `json:"style"`
+}
+
+// pdfFonts are the standard PDF fonts text is drawn in, which every PDF viewer has, by resource name
+var pdfFonts = []struct {
+	name     string
+	baseFont string
+}{{"F1", "Times-Roman"}, {"F2", "Helvetica"}, {"F3", "Courier"}}
+
+// writePDF writes a single page vector PDF document drawing a graph laid out by Graphviz, from its json output.
+// The bundled Graphviz has no PDF renderer, so its drawing operations are turned into PDF ones here. Text is drawn
+// in the standard PDF font closest to the font it was laid out with, and Tasks with a URL link to it.
+func writePDF(w io.Writer, layout []byte) error {
+	var g xdotGraph
+	if err := json.Unmarshal(layout, &g); err != nil {
+		return fmt.Errorf("Failed to read the Graphviz layout: %w", err)
+	}
+	bb := parseFloats(g.BB)
+	if len(bb) != 4 {
+		return fmt.Errorf("Graphviz layout has an invalid bounding box \"%s\"", g.BB)
+	}
+	width, height := bb[2], bb[3]
+
+	var contents bytes.Buffer
+	draw := func(ops ...[]xdotOp) {
+		for _, o := range ops {
+			writePDFOps(&contents, o)
+		}
+	}
+	draw(g.Draw, g.LDraw)
+	// clusters come first, drawn from the outside in so nested clusters are drawn over their parents
+	subgraphs := g.SubgraphCount
+	if subgraphs > len(g.Objects) {
+		subgraphs = len(g.Objects)
+	}
+	clusters := append([]xdotObject{}, g.Objects[:subgraphs]...)
+	parents := make(map[int]int)
+	for _, c := range clusters {
+		for _, child := range c.Subgraphs {
+			parents[child] = c.ID
+		}
+	}
+	depths := make(map[int]int)
+	for _, c := range clusters {
+		for id, nested := c.ID, true; nested; id, nested = parents[id] {
+			depths[c.ID]++
+		}
+	}
+	sort.SliceStable(clusters, func(i, j int) bool { return depths[clusters[i].ID] < depths[clusters[j].ID] })
+	for _, c := range clusters {
+		draw(c.Draw, c.LDraw)
+	}
+	for _, e := range g.Edges {
+		draw(e.Draw, e.HDraw, e.TDraw, e.LDraw, e.HLDraw, e.TLDraw)
+	}
+	links := []string{}
+	for _, n := range g.Objects[subgraphs:] {
+		draw(n.Draw, n.LDraw)
+		pos, nodeWidth, nodeHeight := parseFloats(n.Pos), parseFloats(n.Width), parseFloats(n.Height)
+		if n.URL != "" && len(pos) == 2 && len(nodeWidth) == 1 && len(nodeHeight) == 1 {
+			// node sizes are in inches, which are 72 points
+			rx, ry := nodeWidth[0]*36, nodeHeight[0]*36
+			rect := []float64{pos[0] - rx, pos[1] - ry, pos[0] + rx, pos[1] + ry}
+			links = append(links, fmt.Sprintf("<< /Type /Annot /Subtype /Link /Rect [%s] /Border [0 0 0] /A << /S /URI /URI %s >> >>",
+				pdfNumbers(rect...), pdfString(n.URL)))
+		}
+	}
+
+	var buf bytes.Buffer
+	offsets := []int{}
+	object := func(body string) {
+		offsets = append(offsets, buf.Len())
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+	// the catalog, page tree, page and contents come first, followed by the fonts and then the links
+	fonts := []string{}
+	for i, f := range pdfFonts {
+		fonts = append(fonts, fmt.Sprintf("/%s %d 0 R", f.name, 5+i))
+	}
+	annots := []string{}
+	for i := range links {
+		annots = append(annots, fmt.Sprintf("%d 0 R", 5+len(pdfFonts)+i))
+	}
+	buf.WriteString("%PDF-1.4\n")
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object("<< /Type /Pages /Kids [3 0 R] /Count 1 >>")
+	object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %s] /Resources << /Font << %s >> >> /Contents 4 0 R /Annots [%s] >>",
+		pdfNumbers(width, height), strings.Join(fonts, " "), strings.Join(annots, " ")))
+	object(fmt.Sprintf("<< /Length %d >>\nstream\n%s\nendstream", contents.Len(), contents.Bytes()))
+	for _, f := range pdfFonts {
+		object(fmt.Sprintf("<< /Type /Font /Subtype /Type1 /BaseFont /%s /Encoding /WinAnsiEncoding >>", f.baseFont))
+	}
+	for _, link := range links {
+		object(link)
+	}
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+// writePDFOps writes a list of xdot operations as PDF drawing operators. Each list starts with Graphviz's defaults
+// of black lines, no fill and 14pt Times-Roman.
+func writePDFOps(w *bytes.Buffer, ops []xdotOp) {
+	if len(ops) == 0 {
+		return
+	}
+	pen, fill := "0 0 0", ""
+	font, size := "F1", 14.0
+	w.WriteString("q\n")
+	for _, op := range ops {
+		switch op.Op {
+		case "c":
+			if color, ok := pdfColor(op.Color); ok {
+				pen = color
+			}
+		case "C":
+			if color, ok := pdfColor(op.Color); ok {
+				fill = color
+			}
+		case "S":
+			writePDFStyle(w, op.Style)
+		case "F":
+			font, size = pdfFont(op.Face), op.Size
+		case "P", "p", "L":
+			if len(op.Points) == 0 {
+				continue
+			}
+			fmt.Fprintf(w, "%s m\n", pdfNumbers(op.Points[0][0], op.Points[0][1]))
+			for _, p := range op.Points[1:] {
+				fmt.Fprintf(w, "%s l\n", pdfNumbers(p[0], p[1]))
+			}
+			writePDFPaint(w, op.Op, pen, fill)
+		case "B", "b":
+			if len(op.Points) == 0 {
+				continue
+			}
+			fmt.Fprintf(w, "%s m\n", pdfNumbers(op.Points[0][0], op.Points[0][1]))
+			for i := 1; i+2 < len(op.Points); i += 3 {
+				p := op.Points[i : i+3]
+				fmt.Fprintf(w, "%s c\n", pdfNumbers(p[0][0], p[0][1], p[1][0], p[1][1], p[2][0], p[2][1]))
+			}
+			writePDFPaint(w, op.Op, pen, fill)
+		case "E", "e":
+			if len(op.Rect) != 4 {
+				continue
+			}
+			writePDFEllipse(w, op.Rect[0], op.Rect[1], op.Rect[2], op.Rect[3])
+			writePDFPaint(w, op.Op, pen, fill)
+		case "T":
+			if len(op.Pt) != 2 || pen == "" {
+				continue
+			}
+			x := op.Pt[0]
+			switch op.Align {
+			case "c":
+				x -= op.Width / 2
+			case "r":
+				x -= op.Width
+			}
+			fmt.Fprintf(w, "BT %s rg /%s %s Tf %s Td %s Tj ET\n", pen, font, pdfNumbers(size), pdfNumbers(x, op.Pt[1]), pdfString(op.Text))
+		}
+	}
+	w.WriteString("Q\n")
+}
+
+// writePDFPaint fills and strokes the current path. The "P", "E" and "b" operations fill their shape, and every
+// operation but "L" and "B" closes it. Nothing is filled or stroked in a transparent colour.
+func writePDFPaint(w *bytes.Buffer, op string, pen string, fill string) {
+	closed := op != "L" && op != "B"
+	filled := (op == "P" || op == "E" || op == "b") && fill != ""
+	switch {
+	case filled && pen != "":
+		fmt.Fprintf(w, "%s rg %s RG b\n", fill, pen)
+	case filled:
+		fmt.Fprintf(w, "%s rg f\n", fill)
+	case pen == "":
+		w.WriteString("n\n")
+	case closed:
+		fmt.Fprintf(w, "%s RG s\n", pen)
+	default:
+		fmt.Fprintf(w, "%s RG S\n", pen)
+	}
+}
+
+// writePDFStyle sets the line style for an xdot style, eg "dashed" or "setlinewidth(2)"
+func writePDFStyle(w *bytes.Buffer, style string) {
+	switch {
+	case style == "solid":
+		w.WriteString("[] 0 d\n")
+	case style == "dashed":
+		w.WriteString("[5 2] 0 d\n")
+	case style == "dotted":
+		w.WriteString("[1 3] 0 d\n")
+	case style == "bold":
+		w.WriteString("2 w\n")
+	case strings.HasPrefix(style, "setlinewidth("):
+		if width := parseFloats(strings.TrimSuffix(strings.TrimPrefix(style, "setlinewidth("), ")")); len(width) == 1 {
+			fmt.Fprintf(w, "%s w\n", pdfNumbers(width[0]))
+		}
+	}
+}
+
+// writePDFEllipse adds an ellipse to the current path as four Bézier curves
+func writePDFEllipse(w *bytes.Buffer, x float64, y float64, rx float64, ry float64) {
+	// kappa places the control points so each curve is close to a quarter of an ellipse
+	const kappa = 4 * (math.Sqrt2 - 1) / 3
+	kx, ky := rx*kappa, ry*kappa
+	fmt.Fprintf(w, "%s m\n", pdfNumbers(x+rx, y))
+	fmt.Fprintf(w, "%s c\n", pdfNumbers(x+rx, y+ky, x+kx, y+ry, x, y+ry))
+	fmt.Fprintf(w, "%s c\n", pdfNumbers(x-kx, y+ry, x-rx, y+ky, x-rx, y))
+	fmt.Fprintf(w, "%s c\n", pdfNumbers(x-rx, y-ky, x-kx, y-ry, x, y-ry))
+	fmt.Fprintf(w, "%s c\n", pdfNumbers(x+kx, y-ry, x+rx, y-ky, x+rx, y))
+}
+
+// pdfColor converts a Graphviz colour such as "#d3d3d3" to PDF RGB components. Fully transparent colours, such as
+// "#fffffe00", are returned as "" as nothing should be drawn in them. Colours that can't be read, such as
+// gradients, aren't ok.
+func pdfColor(color string) (string, bool) {
+	if !strings.HasPrefix(color, "#") || (len(color) != 7 && len(color) != 9) {
+		return "", false
+	}
+	if len(color) == 9 && color[7:] == "00" {
+		return "", true
+	}
+	components := []float64{}
+	for i := 1; i < 7; i += 2 {
+		c, err := strconv.ParseUint(color[i:i+2], 16, 8)
+		if err != nil {
+			return "", false
+		}
+		components = append(components, float64(c)/255)
+	}
+	return pdfNumbers(components...), true
+}
+
+// pdfFont returns the resource name of the standard PDF font closest to a font face
+func pdfFont(face string) string {
+	face = strings.ToLower(face)
+	switch {
+	case strings.Contains(face, "courier") || strings.Contains(face, "mono"):
+		return "F3"
+	case strings.Contains(face, "helvetica") || strings.Contains(face, "arial") || strings.Contains(face, "sans"):
+		return "F2"
+	}
+	return "F1"
+}
+
+// pdfNumbers formats numbers for a PDF content stream, separated by spaces
+func pdfNumbers(numbers ...float64) string {
+	formatted := []string{}
+	for _, n := range numbers {
+		s := strconv.FormatFloat(n, 'f', 2, 64)
+		s = strings.TrimRight(strings.TrimRight(s, "0"), ".")
+		if s == "-0" {
+			s = "0"
+		}
+		formatted = append(formatted, s)
+	}
+	return strings.Join(formatted, " ")
+}
+
+// pdfString returns s as a PDF literal string in WinAnsiEncoding, with characters it doesn't have replaced by "?"
+func pdfString(s string) string {
+	var sb strings.Builder
+	sb.WriteByte('(')
+	for _, r := range s {
+		switch {
+		case r == '(' || r == ')' || r == '\\':
+			sb.WriteByte('\\')
+			sb.WriteRune(r)
+		case r >= 0x20 && r < 0x7f:
+			sb.WriteRune(r)
+		case r >= 0xa0 && r <= 0xff:
+			fmt.Fprintf(&sb, "\\%03o", r)
+		default:
+			if code, exists := winAnsi[r]; exists {
+				fmt.Fprintf(&sb, "\\%03o", code)
+			} else {
+				sb.WriteByte('?')
+			}
+		}
+	}
+	sb.WriteByte(')')
+	return sb.String()
+}
+
+// winAnsi holds the codes of the characters outside Latin-1 that WinAnsiEncoding has
+var winAnsi = map[rune]byte{
+	'€': 0x80, '‚': 0x82, 'ƒ': 0x83, '„': 0x84, '…': 0x85, '†': 0x86, '‡': 0x87, 'ˆ': 0x88, '‰': 0x89, 'Š': 0x8a,
+	'‹': 0x8b, 'Œ': 0x8c, 'Ž': 0x8e, '‘': 0x91, '’': 0x92, '“': 0x93, '”': 0x94, '•': 0x95, '–': 0x96, '—': 0x97,
+	'˜': 0x98, '™': 0x99, 'š': 0x9a, '›': 0x9b, 'œ': 0x9c, 'ž': 0x9e, 'Ÿ': 0x9f,
+}
+
+// parseFloats parses a list of numbers separated by commas, as Graphviz writes positions and bounding boxes
+func parseFloats(s string) []float64 {
+	numbers := []float64{}
+	for _, field := range strings.Split(s, ",") {
+		n, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+		if err != nil {
+			return nil
+		}
+		numbers = append(numbers, n)
+	}
+	return numbers
+}
@@ -0,0 +1,261 @@
+package rendering
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/futrli/graphflow"
+)
+
+// TextOptions configures the diagrams written by WriteText and WriteTextPath
+type TextOptions struct {
+	// ASCII draws boxes and arrows with plain ASCII characters, for terminals without Unicode support
+	ASCII bool
+}
+
+// textCharset holds the characters a text diagram is drawn with
+type textCharset struct {
+	topLeft, topRight, bottomLeft, bottomRight, horizontal, vertical string
+	roundTopLeft, roundTopRight, roundBottomLeft, roundBottomRight   string
+	groupTopLeft, groupTopRight, groupBottomLeft, groupBottomRight   string
+	groupHorizontal, groupVertical                                   string
+	branch, lastBranch, line, arrow                                  string
+}
+
+var unicodeCharset = textCharset{
+	topLeft: "┌", topRight: "┐", bottomLeft: "└", bottomRight: "┘", horizontal: "─", vertical: "│",
+	roundTopLeft: "╭", roundTopRight: "╮", roundBottomLeft: "╰", roundBottomRight: "╯",
+	groupTopLeft: "┏", groupTopRight: "┓", groupBottomLeft: "┗", groupBottomRight: "┛",
+	groupHorizontal: "━", groupVertical: "┃",
+	branch: "├", lastBranch: "└", line: "─", arrow: "▶",
+}
+
+var asciiCharset = textCharset{
+	topLeft: "+", topRight: "+", bottomLeft: "+", bottomRight: "+", horizontal: "-", vertical: "|",
+	roundTopLeft: "(", roundTopRight: ")", roundBottomLeft: "(", roundBottomRight: ")",
+	groupTopLeft: "+", groupTopRight: "+", groupBottomLeft: "+", groupBottomRight: "+",
+	groupHorizontal: "=", groupVertical: "|",
+	branch: "+", lastBranch: "`", line: "-", arrow: ">",
+}
+
+// ANSI escape codes used to highlight a path
+const (
+	ansiReset   = "\x1b[0m"
+	ansiTaken   = "\x1b[1;32m" // bold green
+	ansiSkipped = "\x1b[2m"    // dim
+)
+
+// textLine is a line of a text diagram, drawn in an ANSI colour if one is set. The prefix and suffix are the
+// borders of any TaskGroups around it, which aren't coloured.
+type textLine struct {
+	prefix string
+	text   string
+	suffix string
+	color  string
+}
+
+// width returns the number of terminal columns the line takes up
+func (l textLine) width() int {
+	return textWidth(l.prefix + l.text + l.suffix)
+}
+
+// WriteText writes a box and arrow diagram of the graphflow for reading in a terminal. Tasks are drawn in the order
+// they're reached from the StartTask, each followed by arrows to the Tasks its Paths lead to, labelled with their
+// PathCondition. Tasks in a TaskGroup are drawn together inside a box named after the group, with nested TaskGroups
+// drawn as boxes inside it.
+func WriteText(w io.Writer, gf *graphflow.Graphflow, opts TextOptions) error {
+	return writeText(w, gf, newGraphModel(gf, nil, "", RenderOptions{}), nil, opts)
+}
+
+// WriteTextPath behaves like WriteText, with the path recorded in a RunResult highlighted using ANSI colours. Tasks
+// that were executed and Paths that were followed are drawn in bold green, and everything else is dimmed.
+func WriteTextPath(w io.Writer, gf *graphflow.Graphflow, result *graphflow.RunResult, opts TextOptions) error {
+	path := tracedPath(result)
+	return writeText(w, gf, newGraphModel(gf, path, "", RenderOptions{}), path, opts)
+}
+
+// writeText draws a graph model, highlighting the Tasks on the path if one is given
+func writeText(w io.Writer, gf *graphflow.Graphflow, m *graphModel, path *pathModel, opts TextOptions) error {
+	chars := unicodeCharset
+	if opts.ASCII {
+		chars = asciiCharset
+	}
+	labels := make(map[string]string)
+	for _, n := range m.nodes {
+		labels[n.id] = n.label
+	}
+	edges := make(map[string][]*edgeModel)
+	for _, e := range m.edges {
+		edges[e.from] = append(edges[e.from], e)
+	}
+	bw := bufio.NewWriter(w)
+	order := textOrder(gf, m)
+	drawn := make(map[*nodeModel]bool)
+	task := func(n *nodeModel) []textLine {
+		drawn[n] = true
+		return textTask(n, edges[n.id], labels, chars, path)
+	}
+	// draw each TaskGroup where its first Task would be, along with the TaskGroups nested inside it
+	var group func(cluster int) []textLine
+	group = func(cluster int) []textLine {
+		lines := []textLine{}
+		for _, n := range order {
+			if drawn[n] {
+				continue
+			}
+			if n.cluster == cluster {
+				lines = append(lines, task(n)...)
+			} else if child := textAncestor(m, n.cluster, cluster); child >= 0 {
+				lines = append(lines, group(child)...)
+			}
+		}
+		return textGroup(m.clusters[cluster].name, lines, chars)
+	}
+	for _, n := range order {
+		if drawn[n] {
+			continue
+		}
+		if n.cluster < 0 {
+			writeTextLines(bw, task(n))
+		} else {
+			writeTextLines(bw, group(textAncestor(m, n.cluster, -1)))
+		}
+	}
+	return bw.Flush()
+}
+
+// textAncestor returns the cluster nested directly inside the one given as inside that is, or contains, the cluster.
+// It returns -1 if the cluster isn't inside it.
+func textAncestor(m *graphModel, cluster int, inside int) int {
+	for cluster >= 0 {
+		if m.clusters[cluster].parent == inside {
+			return cluster
+		}
+		cluster = m.clusters[cluster].parent
+	}
+	return -1
+}
+
+// textOrder returns the nodes of a graph model ordered by the fewest Paths needed to reach them from a StartTask,
+// keeping the order they were added in otherwise. Tasks that can't be reached come last.
+func textOrder(gf *graphflow.Graphflow, m *graphModel) []*nodeModel {
+	ranks := make(map[string]int)
+	queue := []string{}
+	for _, t := range gf.Tasks() {
+		if _, isStartTask := t.(*graphflow.StartTask); isStartTask {
+			ranks[gf.TaskID(t)] = 0
+			queue = append(queue, gf.TaskID(t))
+		}
+	}
+	for len(queue) > 0 {
+		id := queue[0]
+		queue = queue[1:]
+		for _, e := range m.edges {
+			if _, ranked := ranks[e.to]; e.from == id && !ranked {
+				ranks[e.to] = ranks[id] + 1
+				queue = append(queue, e.to)
+			}
+		}
+	}
+	nodes := append([]*nodeModel{}, m.nodes...)
+	rank := func(n *nodeModel) int {
+		if r, ranked := ranks[n.id]; ranked {
+			return r
+		}
+		return len(m.nodes)
+	}
+	sort.SliceStable(nodes, func(i, j int) bool { return rank(nodes[i]) < rank(nodes[j]) })
+	return nodes
+}
+
+// textTask draws a Task as a box followed by an arrow for each of its Paths
+func textTask(n *nodeModel, edges []*edgeModel, labels map[string]string, chars textCharset, path *pathModel) []textLine {
+	topLeft, topRight, bottomLeft, bottomRight := chars.topLeft, chars.topRight, chars.bottomLeft, chars.bottomRight
+	if n.kind == StartKind || n.kind == EndKind {
+		topLeft, topRight = chars.roundTopLeft, chars.roundTopRight
+		bottomLeft, bottomRight = chars.roundBottomLeft, chars.roundBottomRight
+	}
+	color := ""
+	if path != nil {
+		color = ansiSkipped
+		if path.tasks[n.id] {
+			color = ansiTaken
+		}
+	}
+	border := strings.Repeat(chars.horizontal, textWidth(n.label)+2)
+	lines := []textLine{
+		{text: topLeft + border + topRight, color: color},
+		{text: fmt.Sprintf("%s %s %s", chars.vertical, n.label, chars.vertical), color: color},
+		{text: bottomLeft + border + bottomRight, color: color},
+	}
+	width := 0
+	for _, e := range edges {
+		if textWidth(e.label) > width {
+			width = textWidth(e.label)
+		}
+	}
+	for i, e := range edges {
+		branch := chars.branch
+		if i == len(edges)-1 {
+			branch = chars.lastBranch
+		}
+		label := strings.Repeat(chars.line, width-textWidth(e.label)+2)
+		if e.label != "" {
+			label = fmt.Sprintf("%s%s%s", chars.line, e.label, strings.Repeat(chars.line, width-textWidth(e.label)+1))
+		}
+		color := ""
+		if path != nil {
+			color = ansiSkipped
+			if e.taken {
+				color = ansiTaken
+			}
+		}
+		lines = append(lines, textLine{
+			text:  fmt.Sprintf("  %s%s%s %s", branch, label, chars.arrow, labels[e.to]),
+			color: color,
+		})
+	}
+	return lines
+}
+
+// textGroup draws the lines of a TaskGroup's Tasks inside a box, with the group's name in its top border
+func textGroup(name string, lines []textLine, chars textCharset) []textLine {
+	width := textWidth(name) + 4
+	for _, l := range lines {
+		if l.width()+2 > width {
+			width = l.width() + 2
+		}
+	}
+	title := fmt.Sprintf("%s %s ", chars.groupHorizontal, name)
+	boxed := []textLine{{text: chars.groupTopLeft + title + strings.Repeat(chars.groupHorizontal, width-textWidth(title)) + chars.groupTopRight}}
+	for _, l := range lines {
+		padding := strings.Repeat(" ", width-l.width()-1)
+		boxed = append(boxed, textLine{
+			prefix: chars.groupVertical + " " + l.prefix,
+			text:   l.text,
+			suffix: l.suffix + padding + chars.groupVertical,
+			color:  l.color,
+		})
+	}
+	return append(boxed, textLine{text: chars.groupBottomLeft + strings.Repeat(chars.groupHorizontal, width) + chars.groupBottomRight})
+}
+
+// writeTextLines writes lines, wrapping any coloured text in ANSI escape codes
+func writeTextLines(w io.Writer, lines []textLine) {
+	for _, l := range lines {
+		text := l.text
+		if l.color != "" {
+			text = l.color + text + ansiReset
+		}
+		fmt.Fprintln(w, strings.TrimRight(l.prefix+text+l.suffix, " "))
+	}
+}
+
+// textWidth returns the number of terminal columns a string takes up, assuming every character takes one column
+func textWidth(s string) int {
+	return utf8.RuneCountInString(s)
+}
@@ -0,0 +1,95 @@
+package rendering
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/futrli/graphflow"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWriteText(t *testing.T) {
+	gf := buildGraphflow()
+	gf.NewTaskGroup("Forecasting").AddTasks(gf.Task("forecast-rain"), gf.Task("forecast-sun"))
+
+	var buf bytes.Buffer
+	err := WriteText(&buf, gf, TextOptions{ASCII: true})
+
+	assert.Nil(t, err)
+	assert.Equal(t, `(-------)
+| Start |
+(-------)
+  `+"`"+`--> Is the sky cloudy?
++--------------------+
+| Is the sky cloudy? |
++--------------------+
+  +-YES-> Forecast Rain
+  `+"`"+`-NO--> Forecast Sun
++= Forecasting =====+
+| +---------------+ |
+| | Forecast Rain | |
+| +---------------+ |
+|   `+"`"+`--> End        |
+| +--------------+  |
+| | Forecast Sun |  |
+| +--------------+  |
+|   `+"`"+`--> End        |
++===================+
+(-----)
+| End |
+(-----)
+`, buf.String())
+}
+
+func TestWriteTextNestsTaskGroups(t *testing.T) {
+	gf := buildGraphflow()
+	forecasting := gf.NewTaskGroup("Forecasting")
+	forecasting.AddTasks(gf.Task("forecast-sun"))
+	forecasting.NewTaskGroup("Rain").AddTasks(gf.Task("forecast-rain"))
+
+	var buf bytes.Buffer
+	err := WriteText(&buf, gf, TextOptions{ASCII: true})
+
+	assert.Nil(t, err)
+	assert.Contains(t, buf.String(), `+= Forecasting =========+
+| += Rain ============+ |
+| | +---------------+ | |
+| | | Forecast Rain | | |
+| | +---------------+ | |
+| |   `+"`"+`--> End        | |
+| +===================+ |
+| +--------------+      |
+| | Forecast Sun |      |
+| +--------------+      |
+|   `+"`"+`--> End            |
++=======================+
+`)
+}
+
+func TestWriteTextUnicode(t *testing.T) {
+	gf := buildGraphflow()
+
+	var buf bytes.Buffer
+	err := WriteText(&buf, gf, TextOptions{})
+
+	assert.Nil(t, err)
+	assert.Contains(t, buf.String(), "│ Is the sky cloudy? │\n")
+	assert.Contains(t, buf.String(), "  ├─YES─▶ Forecast Rain\n  └─NO──▶ Forecast Sun\n")
+	assert.NotContains(t, buf.String(), "\x1b[")
+}
+
+func TestWriteTextPath(t *testing.T) {
+	ctx := new(graphflow.ExecutionContext)
+	ctx.Set("Sky", "Clear")
+	gf := buildGraphflow()
+	gf.Run(ctx)
+
+	var buf bytes.Buffer
+	err := WriteTextPath(&buf, gf, gf.Result(), TextOptions{ASCII: true})
+
+	assert.Nil(t, err)
+	assert.Contains(t, buf.String(), "\x1b[1;32m| Forecast Sun |\x1b[0m\n")
+	assert.Contains(t, buf.String(), "\x1b[2m| Forecast Rain |\x1b[0m\n")
+	assert.Contains(t, buf.String(), "\x1b[2m  +-YES-> Forecast Rain\x1b[0m\n")
+	assert.Contains(t, buf.String(), "\x1b[1;32m  `-NO--> Forecast Sun\x1b[0m\n")
+}
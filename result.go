@@ -0,0 +1,56 @@
+package graphflow
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// stepJSON is how a Step is marshalled to JSON
+type stepJSON struct {
+	TaskID   string                 `json:"task"`
+	ExitPath string                 `json:"exitPath"`
+	Started  time.Time              `json:"started"`
+	Duration time.Duration          `json:"duration"`
+	Context  map[string]interface{} `json:"context,omitempty"`
+	Error    string                 `json:"error,omitempty"`
+	Owner    string                 `json:"owner,omitempty"`
+	OverSLA  bool                   `json:"overSLA,omitempty"`
+}
+
+// MarshalJSON marshals the Step with its ExitPath by name, eg "YES"
+func (s Step) MarshalJSON() ([]byte, error) {
+	return json.Marshal(stepJSON{
+		TaskID:   s.TaskID,
+		ExitPath: PathConditionName[s.ExitPath],
+		Started:  s.Started,
+		Duration: s.Duration,
+		Context:  s.Context,
+		Error:    s.Error,
+		Owner:    s.Owner,
+		OverSLA:  s.OverSLA,
+	})
+}
+
+// UnmarshalJSON unmarshals a Step marshalled by MarshalJSON. Values in its Context are unmarshalled as JSON values,
+// so numbers become float64s and structs become maps.
+func (s *Step) UnmarshalJSON(b []byte) error {
+	var j stepJSON
+	if err := json.Unmarshal(b, &j); err != nil {
+		return err
+	}
+	exitPath, err := ParsePathCondition(j.ExitPath)
+	if err != nil {
+		return err
+	}
+	*s = Step{
+		TaskID:   j.TaskID,
+		ExitPath: exitPath,
+		Started:  j.Started,
+		Duration: j.Duration,
+		Context:  j.Context,
+		Error:    j.Error,
+		Owner:    j.Owner,
+		OverSLA:  j.OverSLA,
+	}
+	return nil
+}
@@ -0,0 +1,46 @@
+package graphflow
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunResultJSON(t *testing.T) {
+	ctx := new(ExecutionContext)
+	ctx.Set("Sky", "Cloudy")
+	gf := buildGraphflow()
+	gf.SetVersion("v1")
+	gf.RecordContext(true)
+	assert.Nil(t, gf.Run(ctx))
+
+	b, err := json.Marshal(gf.Result())
+
+	assert.Nil(t, err)
+	assert.Contains(t, string(b), `"task":"is-the-sky-cloudy","exitPath":"YES"`)
+	assert.Contains(t, string(b), `"version":"v1"`)
+
+	var result RunResult
+	err = json.Unmarshal(b, &result)
+
+	assert.Nil(t, err)
+	assert.True(t, result.Ended)
+	assert.Equal(t, gf.Result().Hash, result.Hash)
+	assert.Len(t, result.Steps, 4)
+	for i, step := range result.Steps {
+		expected := gf.Result().Steps[i]
+		assert.Equal(t, expected.TaskID, step.TaskID)
+		assert.Equal(t, expected.ExitPath, step.ExitPath)
+		assert.Equal(t, expected.Duration, step.Duration)
+		assert.True(t, expected.Started.Equal(step.Started))
+		assert.Equal(t, "Cloudy", step.Context["Sky"])
+	}
+}
+
+func TestRunResultJSONWithUnknownExitPathThrowsError(t *testing.T) {
+	var result RunResult
+	err := json.Unmarshal([]byte(`{"steps":[{"task":"start","exitPath":"MAYBE"}]}`), &result)
+
+	assert.EqualError(t, err, "\"MAYBE\" is not a PathCondition")
+}
@@ -0,0 +1,130 @@
+package graphflow
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Route is a distinct way through a graphflow from a StartTask to an EndTask
+type Route struct {
+	// TaskIDs are the IDs of the Tasks on the Route, in order
+	TaskIDs []string
+	// Conditions are the PathConditions followed from each Task on the Route but the last
+	Conditions []PathCondition
+	// Outcome is the outcome name of the EndTask the Route finishes at, empty for the default EndTask
+	Outcome string
+}
+
+// Decision is a PathCondition other than ALWAYS followed from a Task on a Route, eg the answer to a question
+type Decision struct {
+	TaskID    string
+	Condition PathCondition
+}
+
+// RouteOptions configures how AllPathsWith explores a graphflow
+type RouteOptions struct {
+	// MaxVisits is the number of times a Task can appear on a Route, so loops are followed a bounded number of
+	// times. It defaults to 1, which matches Run, as Run executes each Task at most once. Routes visiting a Task
+	// more than once can't be taken by Run, so they're only useful for analysis, and traced paths never have loop
+	// counts for them.
+	MaxVisits int
+	// Entry limits the Routes to those from the StartTask for the named entry point. All entry points are included
+	// if it's empty.
+	Entry string
+}
+
+// Decisions returns the PathConditions other than ALWAYS followed along the Route
+func (r Route) Decisions() []Decision {
+	decisions := []Decision{}
+	for i, condition := range r.Conditions {
+		if condition != ALWAYS {
+			decisions = append(decisions, Decision{TaskID: r.TaskIDs[i], Condition: condition})
+		}
+	}
+	return decisions
+}
+
+// String returns the Route as Task IDs joined by arrows, labelled with any PathConditions other than ALWAYS,
+// eg "start -> is-the-sky-cloudy -YES-> forecast-rain -> end"
+func (r Route) String() string {
+	var sb strings.Builder
+	for i, id := range r.TaskIDs {
+		if i > 0 {
+			if condition := r.Conditions[i-1]; condition != ALWAYS {
+				fmt.Fprintf(&sb, " -%s-> ", PathConditionName[condition])
+			} else {
+				sb.WriteString(" -> ")
+			}
+		}
+		sb.WriteString(id)
+	}
+	return sb.String()
+}
+
+// AllPaths returns every distinct Route from a StartTask to an EndTask, without visiting any Task twice. Each of a
+// Task's Paths leads to a different Route, as only one of them is followed when it's executed. A Task's ERROR
+// Path, or the error handler of its TaskGroup, is followed as the ERROR PathCondition.
+func (gf *Graphflow) AllPaths() []Route {
+	return gf.AllPathsWith(RouteOptions{})
+}
+
+// AllPathsWith behaves like AllPaths, with options to follow loops and to pick an entry point
+func (gf *Graphflow) AllPathsWith(opts RouteOptions) []Route {
+	if opts.MaxVisits < 1 {
+		opts.MaxVisits = 1
+	}
+	routes := []Route{}
+	visits := make(map[TaskIntf]int)
+	tasks := []TaskIntf{}
+	conditions := []PathCondition{}
+	var explore func(task TaskIntf)
+	explore = func(task TaskIntf) {
+		if visits[task] == opts.MaxVisits {
+			return
+		}
+		visits[task]++
+		tasks = append(tasks, task)
+		defer func() {
+			visits[task]--
+			tasks = tasks[:len(tasks)-1]
+		}()
+		if endTask, isEndTask := task.(*EndTask); isEndTask {
+			route := Route{Conditions: append([]PathCondition{}, conditions...), Outcome: endTask.outcome}
+			for _, t := range tasks {
+				route.TaskIDs = append(route.TaskIDs, gf.ids[t])
+			}
+			routes = append(routes, route)
+			return
+		}
+		for _, condition := range gf.routeConditions(task) {
+			to := gf.paths[task][condition]
+			if condition == ERROR && to == nil {
+				to = gf.errorHandler(task)
+			}
+			conditions = append(conditions, condition)
+			explore(to)
+			conditions = conditions[:len(conditions)-1]
+		}
+	}
+	for _, task := range gf.tasks {
+		if startTask, isStartTask := task.(*StartTask); isStartTask && (opts.Entry == "" || startTask.name == opts.Entry) {
+			explore(task)
+		}
+	}
+	return routes
+}
+
+// routeConditions returns the PathConditions that can be followed from a Task in order, including ERROR if the
+// Task's TaskGroup has an error handler
+func (gf *Graphflow) routeConditions(task TaskIntf) []PathCondition {
+	conditions := []PathCondition{}
+	for condition := range gf.paths[task] {
+		conditions = append(conditions, condition)
+	}
+	if _, exists := gf.paths[task][ERROR]; !exists && len(conditions) > 0 && gf.errorHandler(task) != nil {
+		conditions = append(conditions, ERROR)
+	}
+	sort.Slice(conditions, func(i, j int) bool { return conditions[i] < conditions[j] })
+	return conditions
+}
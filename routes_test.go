@@ -0,0 +1,72 @@
+package graphflow
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAllPaths(t *testing.T) {
+	gf := buildGraphflow()
+
+	routes := gf.AllPaths()
+
+	assert.Len(t, routes, 2)
+	assert.Equal(t, []string{"start", "is-the-sky-cloudy", "forecast-rain", "end"}, routes[0].TaskIDs)
+	assert.Equal(t, []PathCondition{ALWAYS, YES, ALWAYS}, routes[0].Conditions)
+	assert.Equal(t, "start -> is-the-sky-cloudy -NO-> forecast-sun -> end", routes[1].String())
+	assert.Equal(t, []Decision{{TaskID: "is-the-sky-cloudy", Condition: NO}}, routes[1].Decisions())
+}
+
+func TestAllPathsReportsOutcomes(t *testing.T) {
+	gf := buildOutcomeGraphflow()
+
+	routes := gf.AllPaths()
+
+	assert.Len(t, routes, 2)
+	assert.Equal(t, "Take Umbrella", routes[0].Outcome)
+	assert.Equal(t, "Wear Sunglasses", routes[1].Outcome)
+}
+
+func TestAllPathsBoundsLoops(t *testing.T) {
+	gf := buildGraphflow()
+	// checking the sky again after forecasting rain
+	gf.RemovePath(gf.Task("forecast-rain"), ALWAYS)
+	checkAgain := gf.AddTask(QuestionFunc("Check Again?", func(ctx *ExecutionContext) (bool, error) {
+		return false, nil
+	}))
+	gf.AddPath(gf.Task("forecast-rain"), ALWAYS, checkAgain)
+	gf.AddPath(checkAgain, YES, gf.Task("is-the-sky-cloudy"))
+	gf.AddPath(checkAgain, NO, gf.Task("end"))
+
+	assert.Len(t, gf.AllPaths(), 2)
+
+	routes := gf.AllPathsWith(RouteOptions{MaxVisits: 2})
+
+	assert.Len(t, routes, 4)
+	assert.Equal(t, "start -> is-the-sky-cloudy -YES-> forecast-rain -> check-again -YES-> is-the-sky-cloudy -YES-> forecast-rain -> check-again -NO-> end", routes[0].String())
+}
+
+func TestAllPathsFollowsErrorHandlers(t *testing.T) {
+	gf, _ := buildFailingGraphflow()
+
+	routes := gf.AllPaths()
+
+	assert.Len(t, routes, 4)
+	assert.Equal(t, "start -> is-the-sky-cloudy -NO-> forecast-sun -> fail-once -> end", routes[1].String())
+	assert.Equal(t, "start -> is-the-sky-cloudy -NO-> forecast-sun -> fail-once -ERROR-> report-failure -> end", routes[2].String())
+	assert.Equal(t, "start -> is-the-sky-cloudy -NO-> forecast-sun -ERROR-> report-failure -> end", routes[3].String())
+}
+
+func TestAllPathsFromEntryPoint(t *testing.T) {
+	gf := buildGraphflow()
+	renewal := gf.AddTask(NewStartTask("renewal"))
+	gf.AddPath(renewal, ALWAYS, gf.Task("forecast-sun"))
+
+	assert.Len(t, gf.AllPaths(), 3)
+
+	routes := gf.AllPathsWith(RouteOptions{Entry: "renewal"})
+
+	assert.Len(t, routes, 1)
+	assert.Equal(t, "start-renewal -> forecast-sun -> end", routes[0].String())
+}
@@ -0,0 +1,152 @@
+package graphflow
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+)
+
+// SetVersion sets the version identifier of the graphflow, which is recorded in the RunResult of every Run
+func (gf *Graphflow) SetVersion(version string) {
+	gf.version = version
+}
+
+// Version returns the version identifier of the graphflow set by SetVersion
+func (gf *Graphflow) Version() string {
+	return gf.version
+}
+
+// Hash returns a hex encoded SHA-256 hash of the content of the graphflow: its Tasks' IDs, types, names and
+// parameters, its Paths and its TaskGroups. Two graphflows built the same way have the same Hash, whatever
+// their version, so the Hash recorded in a RunResult shows whether a graphflow has changed since it was run.
+func (gf *Graphflow) Hash() string {
+	type task struct {
+		ID     string
+		Type   string
+		Name   string
+		Params map[string]interface{} `json:",omitempty"`
+	}
+	content := struct {
+		Tasks  []task
+		Paths  []PathChange
+		Groups []GroupDefinition
+	}{
+		Paths: pathsByID(gf),
+	}
+	for _, t := range gf.tasks {
+		c := task{
+			ID:   gf.ids[t],
+			Type: fmt.Sprintf("%T", t),
+			Name: t.String(),
+		}
+		if p, ok := t.(ParamsProvider); ok {
+			c.Params = p.Params()
+		}
+		content.Tasks = append(content.Tasks, c)
+	}
+	for _, taskGroup := range gf.taskGroups {
+		content.Groups = append(content.Groups, gf.groupDefinition(taskGroup))
+	}
+	b, err := json.Marshal(content)
+	if err != nil {
+		// parameters that can't be marshalled fall back on their printed form
+		b = []byte(fmt.Sprintf("%#v", content))
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:])
+}
+
+// Resume continues a run that stopped with an error, starting again at the Task that failed. The RunResult
+// acts as a checkpoint, so it needs to have been produced by a graphflow with the same Hash.
+func (gf *Graphflow) Resume(checkpoint *RunResult, context *ExecutionContext) error {
+	if checkpoint.Next == "" {
+		return fmt.Errorf("Run of version \"%s\" has nothing left to resume", checkpoint.Version)
+	}
+	if hash := gf.Hash(); checkpoint.Hash != hash {
+		return fmt.Errorf("Run of version \"%s\" can't be resumed on a graphflow with a different hash", checkpoint.Version)
+	}
+	err := gf.validateTasks()
+	if err != nil {
+		return err
+	}
+	t := gf.Task(checkpoint.Next)
+	if t == nil {
+		return fmt.Errorf("There is no Task with the id \"%s\" to resume from", checkpoint.Next)
+	}
+	gf.context = context
+	gf.executed = make(map[TaskIntf]bool)
+	gf.result = &RunResult{
+		Version: gf.version,
+		Hash:    checkpoint.Hash,
+		Steps:   append([]Step{}, checkpoint.Steps...),
+	}
+	return gf.execute(t)
+}
+
+// Versions holds several versions of named graphflows, so that a run can be resumed on the version it started
+// with even after a newer version has been added
+type Versions struct {
+	workflows map[string][]*Graphflow
+}
+
+// NewVersions creates an empty Versions registry
+func NewVersions() *Versions {
+	return &Versions{
+		workflows: make(map[string][]*Graphflow),
+	}
+}
+
+// Add adds a version of the named graphflow. The graphflow needs a version identifier, set with SetVersion,
+// that hasn't already been added for that name.
+func (v *Versions) Add(name string, gf *Graphflow) error {
+	if gf.version == "" {
+		return fmt.Errorf("Graphflow \"%s\" needs a version to be added", name)
+	}
+	if _, err := v.Get(name, gf.version); err == nil {
+		return fmt.Errorf("Graphflow \"%s\" already has a version \"%s\"", name, gf.version)
+	}
+	v.workflows[name] = append(v.workflows[name], gf)
+	return nil
+}
+
+// Get returns the given version of the named graphflow
+func (v *Versions) Get(name string, version string) (*Graphflow, error) {
+	for _, gf := range v.workflows[name] {
+		if gf.version == version {
+			return gf, nil
+		}
+	}
+	return nil, fmt.Errorf("Graphflow \"%s\" has no version \"%s\"", name, version)
+}
+
+// Latest returns the most recently added version of the named graphflow
+func (v *Versions) Latest(name string) (*Graphflow, error) {
+	versions := v.workflows[name]
+	if len(versions) == 0 {
+		return nil, fmt.Errorf("Graphflow \"%s\" has no versions", name)
+	}
+	return versions[len(versions)-1], nil
+}
+
+// Pinned returns the version of the named graphflow that produced a RunResult, checking that its content
+// hasn't changed since
+func (v *Versions) Pinned(name string, result *RunResult) (*Graphflow, error) {
+	gf, err := v.Get(name, result.Version)
+	if err != nil {
+		return nil, err
+	}
+	if gf.Hash() != result.Hash {
+		return nil, fmt.Errorf("Graphflow \"%s\" version \"%s\" has changed since it was run", name, result.Version)
+	}
+	return gf, nil
+}
+
+// Resume continues a run of the named graphflow on the version it started with
+func (v *Versions) Resume(name string, checkpoint *RunResult, context *ExecutionContext) (*Graphflow, error) {
+	gf, err := v.Pinned(name, checkpoint)
+	if err != nil {
+		return nil, err
+	}
+	return gf, gf.Resume(checkpoint, context)
+}
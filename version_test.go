@@ -0,0 +1,147 @@
+package graphflow
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// FailOnce is a Task struct that fails the first time it's executed
+type FailOnce struct {
+	Task
+	failed bool
+}
+
+// String returns a description of the Task
+func (t *FailOnce) String() string {
+	return "Fail Once"
+}
+
+// Execute returns an error the first time it's called
+func (t *FailOnce) Execute(ctx *ExecutionContext) error {
+	if !t.failed {
+		t.failed = true
+		return errors.New("Temporary failure")
+	}
+	ctx.Set("Forecast", "Recovered")
+	return nil
+}
+
+func TestHash(t *testing.T) {
+	gf := buildGraphflow()
+
+	assert.Len(t, gf.Hash(), 64)
+	assert.Equal(t, gf.Hash(), buildGraphflow().Hash())
+	assert.Equal(t, gf.Hash(), gf.Clone().Hash())
+
+	gf.SetVersion("v2") // the version isn't part of the content
+
+	assert.Equal(t, gf.Hash(), buildGraphflow().Hash())
+
+	gf.InsertBetween(gf.Task("forecast-sun"), ALWAYS, new(TaskWithNoName))
+
+	assert.NotEqual(t, gf.Hash(), buildGraphflow().Hash())
+}
+
+func TestRunResultRecordsVersion(t *testing.T) {
+	ctx := new(ExecutionContext)
+	ctx.Set("Sky", "Clear")
+
+	gf := buildGraphflow()
+	gf.SetVersion("2024-01")
+
+	err := gf.Run(ctx)
+
+	assert.Nil(t, err)
+	assert.Equal(t, "2024-01", gf.Version())
+	assert.Equal(t, "2024-01", gf.Result().Version)
+	assert.Equal(t, gf.Hash(), gf.Result().Hash)
+	assert.Equal(t, "", gf.Result().Next)
+}
+
+func TestResume(t *testing.T) {
+	ctx := new(ExecutionContext)
+	ctx.Set("Sky", "Clear")
+
+	gf := buildGraphflow()
+	gf.InsertBetween(gf.Task("forecast-sun"), ALWAYS, new(FailOnce))
+
+	err := gf.Run(ctx)
+
+	assert.NotNil(t, err)
+	checkpoint := gf.Result()
+	assert.Equal(t, "fail-once", checkpoint.Next)
+	assert.Equal(t, "Temporary failure", checkpoint.Error)
+	assert.False(t, checkpoint.Ended)
+
+	err = gf.Resume(checkpoint, ctx)
+
+	assert.Nil(t, err)
+	assert.True(t, gf.Result().Ended)
+	assert.Equal(t, "Recovered", ctx.Get("Forecast"))
+	assert.Len(t, gf.Result().Steps, 5)
+
+	err = gf.Resume(gf.Result(), ctx) // nothing left to resume
+
+	assert.NotNil(t, err)
+}
+
+func TestResumeOnChangedGraphflowThrowsError(t *testing.T) {
+	ctx := new(ExecutionContext)
+	ctx.Set("Sky", "Clear")
+
+	gf := buildGraphflow()
+	gf.InsertBetween(gf.Task("forecast-sun"), ALWAYS, new(FailOnce))
+	gf.Run(ctx)
+	checkpoint := gf.Result()
+
+	gf.InsertBetween(gf.Task("is-the-sky-cloudy"), NO, new(TaskWithNoName))
+
+	err := gf.Resume(checkpoint, ctx)
+
+	assert.NotNil(t, err)
+}
+
+func TestVersions(t *testing.T) {
+	ctx := new(ExecutionContext)
+	ctx.Set("Sky", "Clear")
+
+	v1 := buildGraphflow()
+	v1.InsertBetween(v1.Task("forecast-sun"), ALWAYS, new(FailOnce))
+	v1.SetVersion("v1")
+	v2 := buildGraphflow()
+	v2.SetVersion("v2")
+
+	versions := NewVersions()
+	assert.Nil(t, versions.Add("forecast", v1))
+	assert.NotNil(t, versions.Add("forecast", v1))               // already added
+	assert.NotNil(t, versions.Add("forecast", buildGraphflow())) // no version
+
+	// a run starts on v1 and fails
+	latest, err := versions.Latest("forecast")
+	assert.Nil(t, err)
+	assert.Same(t, v1, latest)
+	assert.NotNil(t, latest.Run(ctx))
+	checkpoint := latest.Result()
+
+	// v2 is released while the v1 run is waiting to be resumed
+	assert.Nil(t, versions.Add("forecast", v2))
+	latest, _ = versions.Latest("forecast")
+	assert.Same(t, v2, latest)
+
+	gf, err := versions.Resume("forecast", checkpoint, ctx)
+
+	assert.Nil(t, err)
+	assert.Same(t, v1, gf)
+	assert.Equal(t, "v1", gf.Result().Version)
+	assert.Equal(t, "Recovered", ctx.Get("Forecast"))
+
+	_, err = versions.Get("forecast", "v3")
+
+	assert.NotNil(t, err)
+
+	_, err = versions.Latest("weather")
+
+	assert.NotNil(t, err)
+}